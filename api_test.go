@@ -0,0 +1,69 @@
+package pcre
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// The identifiers below are defined with either backend, a build tag that
+// lacks one fails to build this file.
+var (
+	_ = []interface{}{Major, Minor, Date, Extra, UTF16, UTF32, NoUTF16Check, NoUTF32Check}
+
+	_ = []Info{
+		InfoOptions, InfoAllOptions, InfoArgOptions, InfoBSR, InfoNewline, InfoSize,
+		InfoCaptureCount, InfoBackrefMax, InfoFirstByte, InfoFirstChar, InfoFirstTable,
+		InfoLastLiteral, InfoNameEntrySize, InfoNameCount, InfoNameTable, InfoStudySize,
+		InfoDefaultTables, InfoOkPartial, InfoJchanged, InfoHasCRorLF, InfoMinLength,
+		InfoJIT, InfoJITSize, InfoMaxLookBehind, InfoMatchEmpty,
+	}
+
+	_ = PatternInfo{
+		Options: 0, Size: 0, CaptureCount: 0, BackrefMax: 0, FirstByte: 0, FirstTable: nil,
+		LastLiteral: 0, NameEntrySize: 0, NameCount: 0, Names: nil, StudySize: 0, MinLength: 0,
+		MaxLookBehind: 0, MatchEmpty: false, OkPartial: false, JChanged: false, HasCRorLF: false,
+		JIT: false, JITSize: 0,
+	}
+)
+
+// errorCodes are the Error values of both backends.
+var errorCodes = []Error{
+	ErrNoMatch, ErrNull, ErrBadOption, ErrBadMagic, ErrUnknownOpcode, ErrUnknownNode,
+	ErrNoMemory, ErrNoSubstring, ErrNoUniqueSubstring, ErrMatchLimit, ErrCallout,
+	ErrBadUTF8, ErrBadUTF8Offset, ErrShortUTF8, ErrBadUTF16, ErrBadUTF16Offset,
+	ErrShortUTF16, ErrBadUTF32, ErrPartial, ErrBadPartial, ErrInternal, ErrBadCount,
+	ErrDFAUItem, ErrDFAUCond, ErrDFAUMLimit, ErrDFAWSSize, ErrDFARecurse,
+	ErrRecursionLimit, ErrNullWSLimit, ErrBadNewline, ErrBadOffset, ErrRecurseLoop,
+	ErrJITStackLimit, ErrJITBadOption, ErrBadMode, ErrBadEndianness, ErrDFABadRestart,
+	ErrHeapLimit, ErrUnset, ErrBadReplacement,
+}
+
+func TestErrorText(t *testing.T) {
+	for _, e := range errorCodes {
+		if _, ok := errorText[e]; !ok {
+			t.Errorf("no text for error %d", int(e))
+		}
+	}
+}
+
+func TestInfoUnsupported(t *testing.T) {
+	re := mustCompile(t, `a`, 0)
+	var value [8]byte
+	for _, what := range []Info{InfoArgOptions, InfoBSR, InfoNewline, InfoStudySize, InfoDefaultTables, InfoOkPartial, InfoJIT} {
+		if err := re.fullinfo(nil, what, unsafe.Pointer(&value)); err != nil && err != ErrBadOption {
+			t.Errorf("fullinfo(%d) = %v, want nil or ErrBadOption", what, err)
+		}
+	}
+}
+
+func TestOptionTypes(t *testing.T) {
+	for name, option := range map[string]interface{}{
+		"NewlineCR": NewlineCR, "NewlineLF": NewlineLF, "NewlineCRLF": NewlineCRLF,
+		"NewlineAny": NewlineAny, "NewlineAnyCRLF": NewlineAnyCRLF,
+		"BSRAnyCRLF": BSRAnyCRLF, "BSRUnicode": BSRUnicode, "NoStartOptimize": NoStartOptimize,
+	} {
+		if _, ok := option.(CompileOption); !ok {
+			t.Errorf("%s is a %T, want a CompileOption", name, option)
+		}
+	}
+}
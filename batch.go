@@ -0,0 +1,56 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+//
+// int go_pcre_exec(const pcre *code, const pcre_extra *extra, const char *subject, int length, int startOffset, int options, int *oVector, int oVectorSize, pcre_jit_stack *stack);
+//
+// static void go_pcre_exec_batch(const pcre *code, const pcre_extra *extra, const char *subjects, int count, const int *lengths, int options, int *results, int *locs, pcre_jit_stack *stack) {
+//     int oVector[3], i;
+//
+//     for (i = 0; i < count; i++) {
+//         oVector[0] = oVector[1] = -1;
+//         results[i] = go_pcre_exec(code, extra, subjects, lengths[i], 0, options, oVector, 3, stack);
+//         if (results[i] == PCRE_ERROR_NOMATCH) {
+//             oVector[0] = oVector[1] = -1;
+//         }
+//         locs[2*i] = oVector[0];
+//         locs[2*i+1] = oVector[1];
+//         subjects += lengths[i];
+//     }
+// }
+import "C"
+
+import "unsafe"
+
+// ExecBatch matches each of subjects against the compiled pattern from its
+// start, all in a single call into libpcre. The result of subjects[i] is
+// stored in results[i], as Exec would return it. When locs is non-nil the
+// offsets of the match are stored in locs[2*i] and locs[2*i+1], -1 if there
+// was none; for an error they are what Error.Detail expects. results must be
+// at least as long as subjects, and locs twice as long.
+//
+// Only the Limits and JITStack of ctx are used, a nil ctx behaves like
+// Exec. The subjects are copied into one buffer for the call.
+func (pcre *PCRE) ExecBatch(extra *PCREExtra, ctx *MatchContext, subjects []string, options ExecOption, results []Error, locs []int) {
+	if len(subjects) == 0 {
+		return
+	}
+
+	callExtra := (*C.pcre_extra)(unsafe.Pointer(extra))
+	var stack *JITStack
+	if ctx != nil {
+		stack = ctx.JITStack
+		if ctx.Limits != (Limits{}) {
+			callExtra = localExtra(extra)
+			defer extraPool.Put(callExtra)
+			ctx.Limits.apply(callExtra)
+		}
+	}
+
+	b := newBatch(subjects)
+	C.go_pcre_exec_batch((*C.pcre)(unsafe.Pointer(pcre)), callExtra, b.subjectsPtr(), C.int(b.count), b.lengths(), C.int(options), b.results(), b.locs(), stack.ptr())
+	b.done(results, locs)
+}
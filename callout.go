@@ -0,0 +1,140 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// extern int goCallout(pcre_callout_block *block);
+import "C"
+
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+func init() {
+	// Callouts only reach Go for calls that set MatchContext.Callout, for
+	// the others goCallout continues the match like having no callout does.
+	C.pcre_callout = (*[0]byte)(C.goCallout)
+}
+
+// CalloutResult tells PCRE how to go on after a callout.
+type CalloutResult int
+
+const (
+	// CalloutContinue lets matching proceed normally.
+	CalloutContinue CalloutResult = 0
+	// CalloutFail fails the match at the current point, the matcher
+	// backtracks and tries other alternatives.
+	CalloutFail CalloutResult = 1
+	// CalloutAbort stops the whole match, Exec returns ErrCallout.
+	CalloutAbort CalloutResult = C.PCRE_ERROR_CALLOUT
+)
+
+// Callout is called at every (?Cn) point in a pattern, and before each item
+// when it was compiled with AutoCallout. Any negative result other than
+// CalloutAbort also stops the match, and is returned by Exec as an Error.
+type Callout func(block *CalloutBlock) CalloutResult
+
+// CalloutBlock describes the state of a match at a callout. Its contents are
+// only valid during the callout.
+type CalloutBlock struct {
+	Number          int    // The n in (?Cn), 255 for automatic callouts
+	Subject         string // The subject passed to Exec
+	StartMatch      int    // Offset where the current match attempt started
+	CurrentPosition int    // Current offset in Subject
+	CaptureTop      int    // One more than the highest numbered captured substring so far
+	CaptureLast     int    // Number of the most recently captured substring, -1 if none
+	PatternPosition int    // Offset of the callout in the pattern
+	NextItemLength  int    // Length of the next item in the pattern
+	Mark            string // Most recent (*MARK) name passed, if any
+
+	// OVector holds the start and end offsets of the substrings captured
+	// so far, as far as they fit in the vector given to Exec.
+	OVector []int
+}
+
+// calloutCall is the per call state a callout handle refers to.
+type calloutCall struct {
+	callout     Callout
+	subject     string
+	oVectorSize int
+	panicked    interface{}
+}
+
+// setCallout makes callExtra run callout, it returns a function that has to
+// be called once matching is done.
+func setCallout(callExtra *C.pcre_extra, callout Callout, subject string, oVectorSize int) (done func()) {
+	call := &calloutCall{callout: callout, subject: subject, oVectorSize: oVectorSize}
+
+	// The handle is kept in C memory, callout_data may not hold an integer
+	// nor point to Go memory.
+	handle := (*C.uintptr_t)(C.malloc(C.sizeof_uintptr_t))
+	*handle = C.uintptr_t(cgo.NewHandle(call))
+	callExtra.flags |= C.PCRE_EXTRA_CALLOUT_DATA
+	callExtra.callout_data = unsafe.Pointer(handle)
+
+	return func() {
+		cgo.Handle(*handle).Delete()
+		C.free(unsafe.Pointer(handle))
+		if call.panicked != nil {
+			panic(call.panicked)
+		}
+	}
+}
+
+//export goCallout
+func goCallout(block *C.pcre_callout_block) C.int {
+	if block.callout_data == nil {
+		return 0
+	}
+	call, ok := cgo.Handle(*(*C.uintptr_t)(block.callout_data)).Value().(*calloutCall)
+	if !ok {
+		return 0
+	}
+
+	oVectorSize := int(block.capture_top) * 2
+	if oVectorSize > call.oVectorSize/3*2 {
+		oVectorSize = call.oVectorSize / 3 * 2
+	}
+	oVector := make([]int, oVectorSize)
+	if oVectorSize > 0 {
+		for n, i := range unsafe.Slice(block.offset_vector, oVectorSize) {
+			oVector[n] = int(i)
+		}
+	}
+
+	var mark string
+	if block.version >= 2 && block.mark != nil {
+		mark = C.GoString((*C.char)(unsafe.Pointer(block.mark)))
+	}
+
+	cb := &CalloutBlock{
+		Number:          int(block.callout_number),
+		Subject:         call.subject,
+		StartMatch:      int(block.start_match),
+		CurrentPosition: int(block.current_position),
+		CaptureTop:      int(block.capture_top),
+		CaptureLast:     int(block.capture_last),
+		PatternPosition: int(block.pattern_position),
+		NextItemLength:  int(block.next_item_length),
+		Mark:            mark,
+		OVector:         oVector,
+	}
+	return C.int(call.run(cb))
+}
+
+// run calls the callout, a panic aborts the match and is raised again once
+// control is back in Go.
+func (call *calloutCall) run(block *CalloutBlock) (result CalloutResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.panicked = r
+			result = CalloutAbort
+		}
+	}()
+	return call.callout(block)
+}
@@ -0,0 +1,181 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCalloutContinue(t *testing.T) {
+	re := mustCompile(t, `a(?C1)b(?C2)c`, 0)
+
+	var blocks []CalloutBlock
+	ctx := &MatchContext{Callout: func(block *CalloutBlock) CalloutResult {
+		blocks = append(blocks, *block)
+		return CalloutContinue
+	}}
+	oVector := make([]int, 3)
+	if e := re.ExecContext(nil, ctx, "xabc", 0, 0, oVector); e < 0 {
+		t.Fatalf("ExecContext = %v", e)
+	}
+	if !reflect.DeepEqual(oVector[:2], []int{1, 4}) {
+		t.Errorf("match at %v, want [1 4]", oVector[:2])
+	}
+
+	if len(blocks) != 2 {
+		t.Fatalf("%d callouts, want 2", len(blocks))
+	}
+	for i, want := range []struct{ number, position, patternPosition int }{{1, 2, 6}, {2, 3, 12}} {
+		block := blocks[i]
+		if block.Number != want.number || block.CurrentPosition != want.position || block.PatternPosition != want.patternPosition {
+			t.Errorf("callout %d: Number %d, CurrentPosition %d, PatternPosition %d, want %d, %d, %d", i,
+				block.Number, block.CurrentPosition, block.PatternPosition, want.number, want.position, want.patternPosition)
+		}
+		if block.Subject != "xabc" || block.StartMatch != 1 {
+			t.Errorf("callout %d: Subject %q, StartMatch %d, want \"xabc\", 1", i, block.Subject, block.StartMatch)
+		}
+	}
+
+	// Without a Callout in the context the callout points are passed over.
+	calls := len(blocks)
+	if e := re.Exec(nil, "xabc", 0, 0, nil); e < 0 {
+		t.Fatalf("Exec = %v", e)
+	}
+	if len(blocks) != calls {
+		t.Error("callout ran for Exec without a MatchContext")
+	}
+}
+
+func TestCalloutFail(t *testing.T) {
+	re := mustCompile(t, `(?C1)a`, 0)
+
+	ctx := &MatchContext{Callout: func(block *CalloutBlock) CalloutResult {
+		if block.CurrentPosition < 2 {
+			return CalloutFail
+		}
+		return CalloutContinue
+	}}
+	oVector := make([]int, 3)
+	if e := re.ExecContext(nil, ctx, "aaa", 0, 0, oVector); e < 0 {
+		t.Fatalf("ExecContext = %v", e)
+	}
+	if !reflect.DeepEqual(oVector[:2], []int{2, 3}) {
+		t.Errorf("match at %v, want [2 3]", oVector[:2])
+	}
+}
+
+func TestCalloutAbort(t *testing.T) {
+	re := mustCompile(t, `a(?C1)b`, 0)
+
+	for _, test := range []struct {
+		result CalloutResult
+		want   Error
+	}{
+		{CalloutAbort, ErrCallout},
+		{-42, -42},
+	} {
+		calls := 0
+		ctx := &MatchContext{Callout: func(*CalloutBlock) CalloutResult {
+			calls++
+			return test.result
+		}}
+		if e := re.ExecContext(nil, ctx, "abab", 0, 0, nil); e != test.want {
+			t.Errorf("callout returning %d: ExecContext = %v, want %v", test.result, e, test.want)
+		}
+		if calls != 1 {
+			t.Errorf("callout returning %d: called %d times, want 1", test.result, calls)
+		}
+	}
+}
+
+func TestCalloutPanic(t *testing.T) {
+	re := mustCompile(t, `a(?C1)b`, 0)
+
+	ctx := &MatchContext{Callout: func(*CalloutBlock) CalloutResult { panic("boom") }}
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want boom", r)
+		}
+	}()
+	re.ExecContext(nil, ctx, "ab", 0, 0, nil)
+	t.Error("ExecContext returned after the callout panicked")
+}
+
+func TestCalloutOVector(t *testing.T) {
+	re := mustCompile(t, `(a)(?C1)(b)(*MARK:M)(?C2)`, 0)
+
+	for _, test := range []struct {
+		oVectorSize int
+		want        map[int][]int // Captures seen by callout n, from the second pair on
+	}{
+		{9, map[int][]int{1: {0, 1}, 2: {0, 1, 1, 2}}},
+		{6, map[int][]int{1: {0, 1}, 2: {0, 1}}},
+		{3, map[int][]int{1: {}, 2: {}}},
+	} {
+		got := map[int]*CalloutBlock{}
+		ctx := &MatchContext{Callout: func(block *CalloutBlock) CalloutResult {
+			got[block.Number] = block
+			return CalloutContinue
+		}}
+		if e := re.ExecContext(nil, ctx, "ab", 0, 0, make([]int, test.oVectorSize)); e < 0 {
+			t.Fatalf("ExecContext = %v", e)
+		}
+
+		for n, want := range test.want {
+			block := got[n]
+			if block == nil {
+				t.Errorf("vector of %d: callout %d did not run", test.oVectorSize, n)
+				continue
+			}
+			var captures []int
+			if len(block.OVector) > 2 {
+				captures = block.OVector[2:]
+			}
+			if len(captures) != len(want) || len(want) > 0 && !reflect.DeepEqual(captures, want) {
+				t.Errorf("vector of %d: callout %d saw captures %v, want %v", test.oVectorSize, n, captures, want)
+			}
+			// Captures that do not fit in the vector are not counted.
+			if test.oVectorSize == 9 && (block.CaptureTop != n+1 || block.CaptureLast != n) {
+				t.Errorf("vector of %d: callout %d: CaptureTop %d, CaptureLast %d, want %d, %d",
+					test.oVectorSize, n, block.CaptureTop, block.CaptureLast, n+1, n)
+			}
+		}
+		if mark := got[2].Mark; mark != "M" {
+			t.Errorf("vector of %d: callout 2 saw mark %q, want M", test.oVectorSize, mark)
+		}
+		if mark := got[1].Mark; mark != "" {
+			t.Errorf("vector of %d: callout 1 saw mark %q, want none", test.oVectorSize, mark)
+		}
+	}
+}
+
+func TestCalloutConcurrent(t *testing.T) {
+	re := mustCompile(t, `(?C1)a`, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				calls := 0
+				ctx := &MatchContext{Callout: func(*CalloutBlock) CalloutResult {
+					calls++
+					return CalloutContinue
+				}}
+				if e := re.ExecContext(nil, ctx, "xxa", 0, 0, nil); e < 0 {
+					t.Errorf("ExecContext = %v", e)
+					return
+				}
+				if calls == 0 {
+					t.Error("callout of this call did not run")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,153 @@
+package pcre
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Layout of the character tables, see pcre_maketables.c.
+const (
+	tablesLowerCase = 0
+	tablesFlipCase  = 256
+	tablesClassBits = 512
+	tablesCharTypes = 832
+	tablesLength    = 1088
+
+	cbitSpace  = 0
+	cbitXDigit = 32
+	cbitDigit  = 64
+	cbitUpper  = 96
+	cbitLower  = 128
+	cbitWord   = 160
+	cbitGraph  = 192
+	cbitPrint  = 224
+	cbitPunct  = 256
+	cbitCntrl  = 288
+
+	ctypeSpace  = 0x01
+	ctypeLetter = 0x02
+	ctypeDigit  = 0x04
+	ctypeXDigit = 0x08
+	ctypeWord   = 0x10
+	ctypeMeta   = 0x80
+)
+
+// CharClasses define the character classes of the 256 byte values.
+type CharClasses struct {
+	IsLower, IsUpper, IsDigit, IsXDigit, IsSpace func(b byte) bool
+	IsGraph, IsPrint, IsPunct, IsCntrl           func(b byte) bool
+	ToLower, ToUpper                             func(b byte) byte
+}
+
+// Latin1 classifies bytes as ISO 8859-1 characters, following the Unicode
+// properties of the first 256 code points.
+var Latin1 = CharClasses{
+	IsLower:  func(b byte) bool { return unicode.IsLower(rune(b)) },
+	IsUpper:  func(b byte) bool { return unicode.IsUpper(rune(b)) },
+	IsDigit:  func(b byte) bool { return '0' <= b && b <= '9' },
+	IsXDigit: func(b byte) bool { return strings.IndexByte("0123456789abcdefABCDEF", b) >= 0 },
+	IsSpace:  func(b byte) bool { return b == ' ' || '\t' <= b && b <= '\r' },
+	IsGraph:  func(b byte) bool { return unicode.IsGraphic(rune(b)) && !unicode.IsSpace(rune(b)) },
+	IsPrint:  func(b byte) bool { return unicode.IsPrint(rune(b)) || b == 0xa0 },
+	IsPunct:  func(b byte) bool { return unicode.IsPunct(rune(b)) || unicode.IsSymbol(rune(b)) },
+	IsCntrl:  func(b byte) bool { return unicode.IsControl(rune(b)) },
+	ToLower:  func(b byte) byte { return foldLatin1(b, unicode.ToLower) },
+	ToUpper:  func(b byte) byte { return foldLatin1(b, unicode.ToUpper) },
+}
+
+// foldLatin1 maps b with to, leaving it unchanged if the result is not a
+// Latin-1 character, like ÿ whose upper case is outside it.
+func foldLatin1(b byte, to func(rune) rune) byte {
+	if r := to(rune(b)); r < 256 {
+		return byte(r)
+	}
+	return b
+}
+
+// tablesFromClasses lays out the tables for classes.
+func tablesFromClasses(classes CharClasses) *[tablesLength]byte {
+	var data [tablesLength]byte
+
+	for i := 0; i < 256; i++ {
+		b := byte(i)
+		data[tablesLowerCase+i] = classes.ToLower(b)
+		if classes.IsLower(b) {
+			data[tablesFlipCase+i] = classes.ToUpper(b)
+		} else {
+			data[tablesFlipCase+i] = classes.ToLower(b)
+		}
+
+		isAlpha := classes.IsLower(b) || classes.IsUpper(b)
+		isWord := isAlpha || classes.IsDigit(b) || b == '_'
+		for _, class := range []struct {
+			offset int
+			is     bool
+		}{
+			{cbitDigit, classes.IsDigit(b)},
+			{cbitUpper, classes.IsUpper(b)},
+			{cbitLower, classes.IsLower(b)},
+			{cbitWord, isWord},
+			{cbitSpace, classes.IsSpace(b)},
+			{cbitXDigit, classes.IsXDigit(b)},
+			{cbitGraph, classes.IsGraph(b)},
+			{cbitPrint, classes.IsPrint(b)},
+			{cbitPunct, classes.IsPunct(b)},
+			{cbitCntrl, classes.IsCntrl(b)},
+		} {
+			if class.is {
+				data[tablesClassBits+class.offset+i/8] |= 1 << uint(i&7)
+			}
+		}
+
+		var ctype byte
+		if classes.IsSpace(b) {
+			ctype |= ctypeSpace
+		}
+		if isAlpha {
+			ctype |= ctypeLetter
+		}
+		if classes.IsDigit(b) {
+			ctype |= ctypeDigit
+		}
+		if classes.IsXDigit(b) {
+			ctype |= ctypeXDigit
+		}
+		if isWord {
+			ctype |= ctypeWord
+		}
+		if b != 0 && strings.IndexByte(`\*+?{^.$|()[`, b) >= 0 {
+			ctype |= ctypeMeta
+		}
+		data[tablesCharTypes+i] = ctype
+	}
+
+	return &data
+}
+
+// patternTables keeps the tables of every pattern compiled with them alive
+// until the pattern is freed.
+var patternTables = struct {
+	sync.Mutex
+	m map[*PCRE]*Tables
+}{m: make(map[*PCRE]*Tables)}
+
+func holdTables(pcre *PCRE, tables *Tables) {
+	patternTables.Lock()
+	patternTables.m[pcre] = tables
+	patternTables.Unlock()
+}
+
+func releaseTables(pcre *PCRE) {
+	patternTables.Lock()
+	delete(patternTables.m, pcre)
+	patternTables.Unlock()
+}
+
+// tablesOf returns the tables pcre was compiled with, or nil for the
+// default ones.
+func tablesOf(pcre *PCRE) *Tables {
+	patternTables.Lock()
+	defer patternTables.Unlock()
+	return patternTables.m[pcre]
+}
@@ -0,0 +1,102 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+import "C"
+
+import "unsafe"
+
+// BuildConfig describes how the linked libpcre was built.
+type BuildConfig struct {
+	Version string // As reported by pcre_version, e.g. "8.39 2016-06-14"
+
+	UTF8              bool // UTF-8 support
+	UTF16             bool // UTF-16 support of libpcre16, only known with the pcre16 tag
+	UTF32             bool // UTF-32 support of libpcre32, only known with the pcre32 tag
+	UnicodeProperties bool // \p, \P and \X support, needed by UCP
+
+	JIT       bool   // Just-in-time compiling support
+	JITTarget string // Architecture the JIT generates code for, empty without JIT
+
+	Newline CompileOption // Default newline convention, one of the Newline* options
+	BSR     CompileOption // Default meaning of \R, BSRUnicode or BSRAnyCRLF
+
+	LinkSize             int  // Bytes used for internal offsets in compiled patterns
+	PosixMallocThreshold int  // Captures above which the POSIX wrapper allocates
+	MatchLimit           int  // Default match limit
+	MatchLimitRecursion  int  // Default recursion limit
+	StackRecurse         bool // Whether match() recurses on the machine stack
+}
+
+// Config queries how the linked libpcre was built. As the binding links
+// whatever libpcre is installed, programs that depend on JIT or Unicode
+// support can check for it at startup.
+func Config() BuildConfig {
+	config := BuildConfig{
+		Version:              C.GoString(C.pcre_version()),
+		UTF8:                 configInt(C.PCRE_CONFIG_UTF8) == 1,
+		UTF16:                utf16Config != nil && utf16Config(),
+		UTF32:                utf32Config != nil && utf32Config(),
+		UnicodeProperties:    configInt(C.PCRE_CONFIG_UNICODE_PROPERTIES) == 1,
+		JIT:                  configInt(C.PCRE_CONFIG_JIT) == 1,
+		LinkSize:             configInt(C.PCRE_CONFIG_LINK_SIZE),
+		PosixMallocThreshold: configInt(C.PCRE_CONFIG_POSIX_MALLOC_THRESHOLD),
+		MatchLimit:           configLong(C.PCRE_CONFIG_MATCH_LIMIT),
+		MatchLimitRecursion:  configLong(C.PCRE_CONFIG_MATCH_LIMIT_RECURSION),
+		StackRecurse:         configInt(C.PCRE_CONFIG_STACKRECURSE) == 1,
+	}
+
+	if config.JIT {
+		var target *C.char
+		if C.pcre_config(C.PCRE_CONFIG_JITTARGET, unsafe.Pointer(&target)) == 0 && target != nil {
+			config.JITTarget = C.GoString(target)
+		}
+	}
+
+	switch configInt(C.PCRE_CONFIG_NEWLINE) {
+	case '\r':
+		config.Newline = NewlineCR
+	case '\n':
+		config.Newline = NewlineLF
+	case '\r'<<8 | '\n':
+		config.Newline = NewlineCRLF
+	case -1:
+		config.Newline = NewlineAny
+	case -2:
+		config.Newline = NewlineAnyCRLF
+	}
+
+	if configInt(C.PCRE_CONFIG_BSR) == 1 {
+		config.BSR = BSRAnyCRLF
+	} else {
+		config.BSR = BSRUnicode
+	}
+
+	return config
+}
+
+// utf16Config and utf32Config report UTF support of libpcre16 and
+// libpcre32, they are set when those are linked. The 8-bit pcre_config does
+// not know about them.
+var utf16Config, utf32Config func() bool
+
+// configInt returns the pcre_config value for what, or 0 when the library
+// does not know what.
+func configInt(what C.int) int {
+	var value C.int
+	if C.pcre_config(what, unsafe.Pointer(&value)) != 0 {
+		return 0
+	}
+	return int(value)
+}
+
+// configLong is configInt for the values pcre_config returns as a long.
+func configLong(what C.int) int {
+	var value C.ulong
+	if C.pcre_config(what, unsafe.Pointer(&value)) != 0 {
+		return 0
+	}
+	return int(value)
+}
@@ -0,0 +1,32 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	config := Config()
+	if !strings.HasPrefix(config.Version, fmt.Sprintf("%d.%02d", Major, Minor)) {
+		t.Errorf("Version %q does not match the header version %d.%02d", config.Version, Major, Minor)
+	}
+	if !config.UTF8 {
+		t.Error("UTF8 = false, the binding compiles every regexp pattern with UTF8")
+	}
+	if config.MatchLimit <= 0 || config.LinkSize <= 0 {
+		t.Errorf("MatchLimit %d, LinkSize %d, want both positive", config.MatchLimit, config.LinkSize)
+	}
+	if config.Newline == 0 {
+		t.Error("Newline not set")
+	}
+	if config.JIT != (config.JITTarget != "") {
+		t.Errorf("JIT %v with JITTarget %q", config.JIT, config.JITTarget)
+	}
+	if utf16Config == nil && config.UTF16 || utf32Config == nil && config.UTF32 {
+		t.Error("UTF16 or UTF32 reported without the 16-bit or 32-bit library")
+	}
+}
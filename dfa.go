@@ -0,0 +1,99 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+import "C"
+
+import "unsafe"
+
+// DefaultDFAWorkspaceSize is the size, in ints, of the workspace DFAExec
+// allocates when it is not given one.
+const DefaultDFAWorkspaceSize = 1000
+
+// DFAWorkspace is the scratch space of pcre_dfa_exec. When a partial match
+// is continued with DFARestart the next call must be given the same
+// workspace, which carries the state of the match over.
+type DFAWorkspace struct {
+	ws []C.int
+}
+
+// NewDFAWorkspace returns a workspace of size ints. Patterns with many
+// alternatives need a larger workspace, DFAExec fails with ErrDFAWSSize when
+// it is too small.
+func NewDFAWorkspace(size int) *DFAWorkspace {
+	if size < 20 {
+		size = 20
+	}
+	return &DFAWorkspace{ws: make([]C.int, size)}
+}
+
+// DFAMatches are the matches found by DFAExec. The DFA algorithm finds every
+// match at the first position where the pattern matches, so all of them
+// share one start offset.
+type DFAMatches struct {
+	Start   int   // Offset of the start of the matches
+	Lengths []int // Length of each match, longest first
+
+	// Partial is set when the subject ended in the middle of a match and
+	// PartialSoft or PartialHard was given. Lengths then holds the single
+	// length of the partially matched text, and the match can be continued
+	// by calling DFAExec with DFARestart and the next segment of the input.
+	Partial bool
+}
+
+// DFAExec matches subject using the alternative DFA algorithm, which finds
+// all matches at a position and never backtracks. It does not support back
+// references, and the JIT is not used. DFAExec returns nil when there is no
+// match.
+//
+// Passing DFAShortest stops at the first, shortest, match. To match input
+// that arrives in segments, pass PartialSoft or PartialHard for every
+// segment, and additionally DFARestart with the same ws for each segment
+// after a partial match. A nil ws uses a fresh workspace of
+// DefaultDFAWorkspaceSize, which cannot be restarted.
+func (pcre *PCRE) DFAExec(extra *PCREExtra, ws *DFAWorkspace, subject string, startOffset int, options ExecOption) (*DFAMatches, error) {
+	if ws == nil {
+		ws = NewDFAWorkspace(DefaultDFAWorkspaceSize)
+	}
+
+	// A restarted match reads the workspace, so keep it in case the output
+	// vector turns out too small and the call has to be repeated.
+	var saved []C.int
+	if options&DFARestart != 0 {
+		saved = append(saved, ws.ws...)
+	}
+
+	oVector := make([]C.int, 2*16)
+	for {
+		rc := C.pcre_dfa_exec((*C.pcre)(unsafe.Pointer(pcre)), (*C.pcre_extra)(unsafe.Pointer(extra)),
+			subjectPtr(subject), C.int(len(subject)), C.int(startOffset), C.int(options),
+			&oVector[0], C.int(len(oVector)), &ws.ws[0], C.int(len(ws.ws)))
+
+		switch {
+		case rc == 0:
+			// More matches than fit in the output vector.
+			oVector = make([]C.int, 2*len(oVector))
+			if saved != nil {
+				copy(ws.ws, saved)
+			}
+		case rc > 0:
+			matches := &DFAMatches{Start: int(oVector[0]), Lengths: make([]int, rc)}
+			for i := range matches.Lengths {
+				matches.Lengths[i] = int(oVector[2*i+1] - oVector[2*i])
+			}
+			return matches, nil
+		case Error(rc) == ErrPartial:
+			return &DFAMatches{
+				Start:   int(oVector[0]),
+				Lengths: []int{int(oVector[1] - oVector[0])},
+				Partial: true,
+			}, nil
+		case Error(rc) == ErrNoMatch:
+			return nil, nil
+		default:
+			return nil, Error(rc).Detail([]int{int(oVector[0]), int(oVector[1])})
+		}
+	}
+}
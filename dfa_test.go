@@ -0,0 +1,93 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDFAExec(t *testing.T) {
+	re := mustCompile(t, `<.*>`, 0)
+
+	for _, test := range []struct {
+		options ExecOption
+		want    *DFAMatches
+	}{
+		{0, &DFAMatches{Start: 2, Lengths: []int{12, 8, 3}}},
+		{DFAShortest, &DFAMatches{Start: 2, Lengths: []int{3}}},
+	} {
+		matches, err := re.DFAExec(nil, nil, "x <a> <bc> <d> y", 0, test.options)
+		if err != nil {
+			t.Fatalf("DFAExec with %v: %v", test.options, err)
+		}
+		if !reflect.DeepEqual(matches, test.want) {
+			t.Errorf("DFAExec with %v = %+v, want %+v", test.options, matches, test.want)
+		}
+	}
+
+	if matches, err := re.DFAExec(nil, nil, "no tags", 0, 0); matches != nil || err != nil {
+		t.Errorf("DFAExec without a match = %+v, %v, want nil, nil", matches, err)
+	}
+}
+
+func TestDFAExecManyMatches(t *testing.T) {
+	re := mustCompile(t, `a+`, 0)
+
+	// More matches than the first output vector holds.
+	const n = 40
+	subject := ""
+	for i := 0; i < n; i++ {
+		subject += "a"
+	}
+	matches, err := re.DFAExec(nil, nil, subject, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches.Lengths) != n {
+		t.Fatalf("%d matches, want %d", len(matches.Lengths), n)
+	}
+	for i, length := range matches.Lengths {
+		if length != n-i {
+			t.Errorf("match %d has length %d, want %d", i, length, n-i)
+		}
+	}
+}
+
+func TestDFAExecRestart(t *testing.T) {
+	re := mustCompile(t, `^\d?\d(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)\d\d$`, 0)
+
+	ws := NewDFAWorkspace(DefaultDFAWorkspaceSize)
+	matches, err := re.DFAExec(nil, ws, "2", 0, PartialSoft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&DFAMatches{Start: 0, Lengths: []int{1}, Partial: true}); !reflect.DeepEqual(matches, want) {
+		t.Fatalf("first segment: %+v, want %+v", matches, want)
+	}
+
+	matches, err = re.DFAExec(nil, ws, "3ja", 0, PartialSoft|DFARestart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matches == nil || !matches.Partial {
+		t.Fatalf("second segment: %+v, want a partial match", matches)
+	}
+
+	matches, err = re.DFAExec(nil, ws, "n05", 0, PartialSoft|DFARestart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&DFAMatches{Start: 0, Lengths: []int{3}}); !reflect.DeepEqual(matches, want) {
+		t.Errorf("last segment: %+v, want %+v", matches, want)
+	}
+
+	// A segment that cannot continue the match ends it.
+	if _, err := re.DFAExec(nil, ws, "2", 0, PartialSoft); err != nil {
+		t.Fatal(err)
+	}
+	if matches, err := re.DFAExec(nil, ws, "x", 0, PartialSoft|DFARestart); matches != nil || err != nil {
+		t.Errorf("restart with a mismatch = %+v, %v, want nil, nil", matches, err)
+	}
+}
@@ -0,0 +1,126 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+import "C"
+
+const (
+	ErrNoMatch        Error = C.PCRE_ERROR_NOMATCH
+	ErrNull           Error = C.PCRE_ERROR_NULL
+	ErrBadOption      Error = C.PCRE_ERROR_BADOPTION
+	ErrBadMagic       Error = C.PCRE_ERROR_BADMAGIC
+	ErrUnknownOpcode  Error = C.PCRE_ERROR_UNKNOWN_OPCODE
+	ErrUnknownNode    Error = C.PCRE_ERROR_UNKNOWN_NODE // For backwards compatibility
+	ErrNoMemory       Error = C.PCRE_ERROR_NOMEMORY
+	ErrNoSubstring    Error = C.PCRE_ERROR_NOSUBSTRING
+	ErrMatchLimit     Error = C.PCRE_ERROR_MATCHLIMIT
+	ErrCallout        Error = C.PCRE_ERROR_CALLOUT
+	ErrBadUTF8        Error = C.PCRE_ERROR_BADUTF8
+	ErrBadUTF8Offset  Error = C.PCRE_ERROR_BADUTF8_OFFSET
+	ErrPartial        Error = C.PCRE_ERROR_PARTIAL
+	ErrBadPartial     Error = C.PCRE_ERROR_BADPARTIAL
+	ErrInternal       Error = C.PCRE_ERROR_INTERNAL
+	ErrBadCount       Error = C.PCRE_ERROR_BADCOUNT
+	ErrDFAUItem       Error = C.PCRE_ERROR_DFA_UITEM
+	ErrDFAUCond       Error = C.PCRE_ERROR_DFA_UCOND
+	ErrDFAUMLimit     Error = C.PCRE_ERROR_DFA_UMLIMIT
+	ErrDFAWSSize      Error = C.PCRE_ERROR_DFA_WSSIZE
+	ErrDFARecurse     Error = C.PCRE_ERROR_DFA_RECURSE
+	ErrRecursionLimit Error = C.PCRE_ERROR_RECURSIONLIMIT
+	ErrNullWSLimit    Error = C.PCRE_ERROR_NULLWSLIMIT // No longer used
+	ErrBadNewline     Error = C.PCRE_ERROR_BADNEWLINE
+	ErrBadOffset      Error = C.PCRE_ERROR_BADOFFSET
+	ErrShortUTF8      Error = C.PCRE_ERROR_SHORTUTF8
+	ErrRecurseLoop    Error = C.PCRE_ERROR_RECURSELOOP
+	ErrJITStackLimit  Error = C.PCRE_ERROR_JIT_STACKLIMIT
+	ErrJITBadOption   Error = C.PCRE_ERROR_JIT_BADOPTION
+	ErrBadMode        Error = C.PCRE_ERROR_BADMODE
+	ErrBadEndianness  Error = C.PCRE_ERROR_BADENDIANNESS
+	ErrDFABadRestart  Error = C.PCRE_ERROR_DFA_BADRESTART
+)
+
+// The codes libpcre2 returns that libpcre has no counterpart for, defined
+// so that code written for either backend builds with both. Nothing returns
+// them without the pcre2 build tag. Their values lie in the ranges Error
+// reserves, see there.
+const (
+	ErrNoUniqueSubstring Error = -3050
+	ErrHeapLimit         Error = -3063
+	ErrUnset             Error = -3055
+	ErrBadReplacement    Error = -3035
+)
+
+// Codes of CompileError for some common mistakes, see the compilation error
+// codes in pcreapi(3). libpcre2 numbers them differently.
+const (
+	CompileErrEndBackslash         = 1
+	CompileErrMissingSquareBracket = 6
+	CompileErrRangeOutOfOrder      = 8
+	CompileErrNothingToRepeat      = 9
+	CompileErrMissingParenthesis   = 14
+	CompileErrUnmatchedParenthesis = 22
+)
+
+// libpcre16 and libpcre32 report invalid UTF-16 and UTF-32 with the values
+// of the UTF-8 codes, PCRE16.Exec and PCRE32.Exec return these instead.
+const (
+	ErrBadUTF16       Error = C.PCRE_ERROR_BADUTF16 - 1000
+	ErrBadUTF16Offset Error = C.PCRE_ERROR_BADUTF16_OFFSET - 1000
+	ErrShortUTF16     Error = C.PCRE_ERROR_SHORTUTF16 - 1000
+	ErrBadUTF32       Error = C.PCRE_ERROR_BADUTF32 - 2000
+)
+
+var utf16Errors = map[Error]Error{
+	ErrBadUTF8:       ErrBadUTF16,
+	ErrBadUTF8Offset: ErrBadUTF16Offset,
+	ErrShortUTF8:     ErrShortUTF16,
+}
+
+var utf32Errors = map[Error]Error{
+	ErrBadUTF8: ErrBadUTF32,
+}
+
+var errorText = map[Error]string{
+	ErrNoMatch:        "no match",
+	ErrNull:           "NULL argument",
+	ErrBadOption:      "unrecognized option",
+	ErrBadMagic:       "bad magic number in compiled pattern",
+	ErrUnknownOpcode:  "unknown opcode in compiled pattern",
+	ErrNoMemory:       "out of memory",
+	ErrNoSubstring:    "no such captured substring",
+	ErrMatchLimit:     "match limit exceeded",
+	ErrCallout:        "callout failed",
+	ErrBadUTF8:        "invalid UTF-8 in subject",
+	ErrBadUTF8Offset:  "start offset is not at the start of a UTF-8 character",
+	ErrPartial:        "partial match",
+	ErrBadPartial:     "pattern contains items not supported for partial matching",
+	ErrInternal:       "internal error",
+	ErrBadCount:       "negative output vector size",
+	ErrDFAUItem:       "item not supported by DFA matching",
+	ErrDFAUCond:       "condition not supported by DFA matching",
+	ErrDFAUMLimit:     "match limits not supported by DFA matching",
+	ErrDFAWSSize:      "DFA workspace too small",
+	ErrDFARecurse:     "DFA recursion workspace too small",
+	ErrRecursionLimit: "recursion limit exceeded",
+	ErrNullWSLimit:    "workspace limit exceeded",
+	ErrBadNewline:     "invalid newline option",
+	ErrBadOffset:      "start offset out of range",
+	ErrShortUTF8:      "incomplete UTF-8 character at end of subject",
+	ErrBadUTF16:       "invalid UTF-16 in subject",
+	ErrBadUTF16Offset: "start offset is not at the start of a UTF-16 character",
+	ErrShortUTF16:     "incomplete UTF-16 character at end of subject",
+	ErrBadUTF32:       "invalid UTF-32 in subject",
+	ErrRecurseLoop:    "recursion loop without advancing in subject",
+	ErrJITStackLimit:  "JIT stack limit exceeded",
+	ErrJITBadOption:   "JIT not supported or not compiled for this mode",
+	ErrBadMode:        "pattern compiled in a different 8/16 bit mode",
+	ErrBadEndianness:  "pattern compiled with a different byte order",
+	ErrDFABadRestart:  "DFA restart without matching previous partial match",
+
+	ErrNoUniqueSubstring: "subpattern name is not unique",
+	ErrHeapLimit:         "heap limit exceeded",
+	ErrUnset:             "requested value is not set",
+	ErrBadReplacement:    "bad replacement string",
+}
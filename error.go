@@ -0,0 +1,129 @@
+package pcre
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error is a return code of Exec. Negative values are errors and can be
+// compared against the Err* values, either directly or with errors.Is.
+//
+// Codes from -1 to -999 are the ones the linked library returns. Below that
+// the package reserves ranges for codes it defines itself, each holding the
+// library code n it is derived from at an offset:
+//
+//	-1000+n  UTF-16 variants of the UTF-8 errors, see ErrBadUTF16
+//	-2000+n  UTF-32 variants of the UTF-8 errors, see ErrBadUTF32
+//	-3000+n  codes of the other backend, libpcre's with the pcre2 build tag
+//	         and libpcre2's without it, which the linked library never returns
+//
+// Neither library uses codes below -999, so these never clash with a code
+// libpcre or libpcre2 returns.
+type Error int
+
+func (e Error) Error() string {
+	if text, ok := errorText[e]; ok {
+		return "pcre: " + text
+	}
+	return fmt.Sprintf("pcre: error %d", int(e))
+}
+
+// Detail returns e, the result of an Exec call, as an error. For invalid
+// UTF-8 it returns a *UTF8Error built from the reason and offset pcre_exec
+// stores in oVector, which must have room for at least two entries. Detail
+// returns nil when e is not an error.
+func (e Error) Detail(oVector []int) error {
+	if e >= 0 {
+		return nil
+	}
+	if (e == ErrBadUTF8 || e == ErrShortUTF8) && len(oVector) >= 2 {
+		return &UTF8Error{Code: e, Offset: oVector[0], Reason: UTF8Reason(oVector[1])}
+	}
+	return e
+}
+
+// UTF8Reason tells why a subject was rejected as invalid UTF-8, it is one of
+// the PCRE_UTF8_ERRn codes.
+type UTF8Reason int
+
+var utf8ReasonText = [...]string{
+	1:  "1 byte missing at end",
+	2:  "2 bytes missing at end",
+	3:  "3 bytes missing at end",
+	4:  "4 bytes missing at end",
+	5:  "5 bytes missing at end",
+	6:  "byte 2 top bits not 0x80",
+	7:  "byte 3 top bits not 0x80",
+	8:  "byte 4 top bits not 0x80",
+	9:  "byte 5 top bits not 0x80",
+	10: "byte 6 top bits not 0x80",
+	11: "5-byte character is not allowed (RFC 3629)",
+	12: "6-byte character is not allowed (RFC 3629)",
+	13: "code points greater than 0x10ffff are not defined",
+	14: "code points 0xd800-0xdfff are not defined",
+	15: "overlong 2-byte sequence",
+	16: "overlong 3-byte sequence",
+	17: "overlong 4-byte sequence",
+	18: "overlong 5-byte sequence",
+	19: "overlong 6-byte sequence",
+	20: "isolated byte with 0x80 bit set",
+	21: "illegal byte (0xfe or 0xff)",
+}
+
+func (r UTF8Reason) String() string {
+	if r > 0 && int(r) < len(utf8ReasonText) {
+		return utf8ReasonText[r]
+	}
+	return fmt.Sprintf("UTF-8 error %d", int(r))
+}
+
+// UTF8Error describes a subject that failed the UTF-8 validity check.
+type UTF8Error struct {
+	Code   Error      // ErrBadUTF8 or ErrShortUTF8
+	Offset int        // Byte offset of the start of the bad character
+	Reason UTF8Reason // Why the character is invalid
+}
+
+func (e *UTF8Error) Error() string {
+	return fmt.Sprintf("%s at offset %d: %s", e.Code.Error(), e.Offset, e.Reason)
+}
+
+func (e *UTF8Error) Unwrap() error { return e.Code }
+
+// CompileError describes a pattern that pcre_compile2 rejected.
+type CompileError struct {
+	Message string // Error text reported by libpcre
+	Code    int    // Numeric compile error code
+	Offset  int    // Byte offset in Pattern where the error was detected
+	Pattern string
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("error parsing regexp: %s at offset %d: `%s`", e.Message, e.Offset, e.Pattern)
+}
+
+// Caret renders the pattern on one line and a caret under the character at
+// Offset on the next, for showing the error position to a user.
+func (e *CompileError) Caret() string {
+	offset := e.Offset
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(e.Pattern) {
+		offset = len(e.Pattern)
+	}
+	column := utf8.RuneCountInString(e.Pattern[:offset])
+	return e.Pattern + "\n" + strings.Repeat(" ", column) + "^"
+}
+
+// byteOffset converts offset, counted in code units of which units(r) make
+// up the rune r, to a byte offset in s.
+func byteOffset(s string, offset int, units func(r rune) int) int {
+	for i, r := range s {
+		if offset <= 0 {
+			return i
+		}
+		offset -= units(r)
+	}
+	return len(s)
+}
@@ -0,0 +1,123 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+//
+// int go_pcre_exec(const pcre *code, const pcre_extra *extra, const char *subject, int length, int startOffset, int options, int *oVector, int oVectorSize, pcre_jit_stack *stack);
+//
+// // Finds successive matches from *start, storing the first pairs offset
+// // pairs of each in out unless it is NULL, until max are found or there are
+// // no more. It returns the number found, or an error, and leaves *start and
+// // *options set for resuming. *done is set once the subject is exhausted.
+// static int go_pcre_exec_all(const pcre *code, const pcre_extra *extra, const char *subject, int length, int *start, int *options, int *oVector, int pairs, int *out, int max, pcre_jit_stack *stack, int *done) {
+//     int found = 0, rc, i;
+//
+//     *done = 0;
+//     while (found < max) {
+//         if (*start > length) {
+//             *done = 1;
+//             break;
+//         }
+//         rc = go_pcre_exec(code, extra, subject, length, *start, *options, oVector, 3*pairs, stack);
+//         if (rc == PCRE_ERROR_NOMATCH) {
+//             *done = 1;
+//             break;
+//         } else if (rc < 0) {
+//             return rc;
+//         }
+//         for (i = 0; out != NULL && i < 2*pairs; i++) {
+//             out[2*pairs*found + i] = oVector[i];
+//         }
+//         found++;
+//
+//         // The next match may not be empty where this one ended, and the
+//         // subject was checked for valid UTF-8 by the first.
+//         *options |= PCRE_NOTEMPTY_ATSTART | PCRE_NO_UTF8_CHECK;
+//         *start = oVector[1];
+//     }
+//     return found;
+// }
+import "C"
+
+import (
+	"math"
+	"unsafe"
+)
+
+// ExecAll finds successive non-overlapping matches of the pattern in subject
+// from startOffset, at most n of them if n >= 0. After each match the search
+// resumes where it ended, where it may not match the empty string again. The
+// loop runs in C, so the subject is passed once rather than per match.
+//
+// It returns the first pairs offset pairs of every match end to end, which
+// holds 2*pairs ints per match, -1 for groups that did not take part. Only
+// the Limits and JITStack of ctx are used, ctx may be nil.
+func (pcre *PCRE) ExecAll(extra *PCREExtra, ctx *MatchContext, subject string, startOffset int, options ExecOption, n int, pairs int) ([]int, error) {
+	var locs []int
+	_, err := pcre.execAll(extra, ctx, subject, startOffset, options, n, pairs, &locs)
+	return locs, err
+}
+
+// Count returns the number of matches ExecAll would find in subject, which
+// it counts in a single call into C without storing their offsets.
+func (pcre *PCRE) Count(extra *PCREExtra, ctx *MatchContext, subject string, startOffset int, options ExecOption) (int, error) {
+	return pcre.execAll(extra, ctx, subject, startOffset, options, -1, 1, nil)
+}
+
+// execAll runs the loop of ExecAll, appending the offsets to *locs unless
+// locs is nil, and returns the number of matches.
+func (pcre *PCRE) execAll(extra *PCREExtra, ctx *MatchContext, subject string, startOffset int, options ExecOption, n int, pairs int, locs *[]int) (int, error) {
+	if pairs < 1 {
+		pairs = 1
+	}
+
+	callExtra := (*C.pcre_extra)(unsafe.Pointer(extra))
+	var stack *JITStack
+	if ctx != nil {
+		stack = ctx.JITStack
+		if ctx.Limits != (Limits{}) {
+			callExtra = localExtra(extra)
+			defer extraPool.Put(callExtra)
+			ctx.Limits.apply(callExtra)
+		}
+	}
+
+	oVector := getOVector(3 * pairs)
+	defer putOVector(oVector)
+
+	// Without locs all matches are counted in one call.
+	chunk, outPtr := math.MaxInt32, (*C.int)(nil)
+	if locs != nil {
+		out := getOVector(2 * pairs * execAllChunk)
+		defer putOVector(out)
+		chunk, outPtr = execAllChunk, &(*out)[0]
+	}
+
+	total := 0
+	start, opts, done := C.int(startOffset), C.int(options), C.int(0)
+	for n != 0 && done == 0 {
+		limit := chunk
+		if n > 0 && n < limit {
+			limit = n
+		}
+		found := C.go_pcre_exec_all((*C.pcre)(unsafe.Pointer(pcre)), callExtra, subjectPtr(subject), C.int(len(subject)), &start, &opts,
+			&(*oVector)[0], C.int(pairs), outPtr, C.int(limit), stack.ptr(), &done)
+		if found < 0 {
+			detail := make([]int, 2)
+			copyOVector(detail, (*oVector)[:2])
+			return total, Error(found).Detail(detail)
+		}
+		if locs != nil {
+			for _, i := range unsafe.Slice(outPtr, 2*pairs*int(found)) {
+				*locs = append(*locs, int(i))
+			}
+		}
+		total += int(found)
+		if n > 0 {
+			n -= int(found)
+		}
+	}
+	return total, nil
+}
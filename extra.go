@@ -0,0 +1,70 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+// #include <string.h>
+//
+// static pcre_extra *new_pcre_extra(void) {
+//     pcre_extra *extra = pcre_malloc(sizeof(pcre_extra));
+//     if (extra != NULL) {
+//         memset(extra, 0, sizeof(pcre_extra));
+//     }
+//     return extra;
+// }
+import "C"
+
+import "unsafe"
+
+// NewExtra allocates an empty PCREExtra, for setting limits on a pattern
+// that Study returned no extra data for. Release it with Free.
+func NewExtra() *PCREExtra {
+	extra := C.new_pcre_extra()
+	if extra == nil {
+		panic(ErrNoMemory)
+	}
+	track(unsafe.Pointer(extra))
+	return (*PCREExtra)(unsafe.Pointer(extra))
+}
+
+// Limits bound the work pcre_exec may do before giving up, which protects
+// against catastrophic backtracking. A zero field leaves the limit at the
+// library default. Exceeding a limit makes Exec return ErrMatchLimit or
+// ErrRecursionLimit.
+type Limits struct {
+	Match     uint // Maximum number of internal match() calls
+	Recursion uint // Maximum recursion depth of match()
+}
+
+func (limits Limits) apply(extra *C.pcre_extra) {
+	if limits.Match > 0 {
+		extra.flags |= C.PCRE_EXTRA_MATCH_LIMIT
+		extra.match_limit = C.ulong(limits.Match)
+	}
+	if limits.Recursion > 0 {
+		extra.flags |= C.PCRE_EXTRA_MATCH_LIMIT_RECURSION
+		extra.match_limit_recursion = C.ulong(limits.Recursion)
+	}
+}
+
+// SetLimits makes every Exec with pcreExtra use limits, zero fields clear
+// a previously set limit.
+func (pcreExtra *PCREExtra) SetLimits(limits Limits) {
+	extra := (*C.pcre_extra)(unsafe.Pointer(pcreExtra))
+	extra.flags &^= C.PCRE_EXTRA_MATCH_LIMIT | C.PCRE_EXTRA_MATCH_LIMIT_RECURSION
+	limits.apply(extra)
+}
+
+// Limits returns the limits set on pcreExtra.
+func (pcreExtra *PCREExtra) Limits() Limits {
+	var limits Limits
+	extra := (*C.pcre_extra)(unsafe.Pointer(pcreExtra))
+	if extra.flags&C.PCRE_EXTRA_MATCH_LIMIT != 0 {
+		limits.Match = uint(extra.match_limit)
+	}
+	if extra.flags&C.PCRE_EXTRA_MATCH_LIMIT_RECURSION != 0 {
+		limits.Recursion = uint(extra.match_limit_recursion)
+	}
+	return limits
+}
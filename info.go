@@ -0,0 +1,141 @@
+package pcre
+
+import (
+	"bytes"
+	"log"
+	"unsafe"
+)
+
+// PatternInfo describes a compiled pattern, see pcre_fullinfo(3) and
+// pcre2_pattern_info(3). Both backends fill in every field, those libpcre2
+// has no counterpart for hold the value noted.
+type PatternInfo struct {
+	Options      CompileOption // Compile options, with any changed by (?...) at the start of the pattern
+	Size         int           // Size of the compiled pattern in bytes
+	CaptureCount int           // Number of capturing subpatterns
+	BackrefMax   int           // Highest back reference number, 0 if none
+
+	// FirstByte is the byte every match starts with. Otherwise it is -1
+	// when the pattern only matches at the start of the subject or after a
+	// newline, like ^ in every branch with Multiline, and -2 when there is
+	// no such restriction or the pattern is anchored.
+	FirstByte int
+	// FirstTable is a 256 bit table of the bytes a match can start with,
+	// from the study data with libpcre. It is nil when there is none.
+	FirstTable []byte
+	// LastLiteral is the last byte every match must contain, -1 if none.
+	LastLiteral int
+
+	NameEntrySize int      // Size of each entry in the name table
+	NameCount     int      // Number of named subpatterns
+	Names         []string // Name of each subpattern by number, see NameTable
+
+	StudySize     int  // Size of the study data in bytes, 0 with libpcre2 which has none
+	MinLength     int  // Minimum subject length that can match, -1 without study data with libpcre
+	MaxLookBehind int  // Longest lookbehind in characters
+	MatchEmpty    bool // The pattern can match an empty string
+	OkPartial     bool // Partial matching is supported, always true since libpcre 8.00
+	JChanged      bool // (?J) or (?-J) was used
+	HasCRorLF     bool // The pattern contains an explicit CR or LF
+	JIT           bool // The pattern was successfully JIT compiled
+	JITSize       int  // Size of the JIT compiled code in bytes
+}
+
+// infoUnsupported is the value of the Info constants the linked library has
+// no counterpart for, fullinfo fails on it with ErrBadOption.
+const infoUnsupported = -1
+
+// infoInt returns an int valued piece of information about pcre.
+func (pcre *PCRE) infoInt(what Info) (int, error) {
+	var i int32
+	if err := pcre.fullinfo(nil, what, unsafe.Pointer(&i)); err != nil {
+		return 0, err
+	}
+	return int(i), nil
+}
+
+func (pcre *PCRE) CaptureCount() int {
+	i, err := pcre.infoInt(InfoCaptureCount)
+	if err != nil {
+		log.Panicf("pcre_fullinfo: %v", err)
+	}
+	return i
+}
+
+func (pcre *PCRE) NameCount() int {
+	i, err := pcre.infoInt(InfoNameCount)
+	if err != nil {
+		log.Panicf("pcre_fullinfo: %v", err)
+	}
+	return i
+}
+
+func (pcre *PCRE) NameEntrySize() int {
+	i, err := pcre.infoInt(InfoNameEntrySize)
+	if err != nil {
+		log.Panicf("pcre_fullinfo: %v", err)
+	}
+	return i
+}
+
+// NameTable returns the name of every subpattern indexed by its number, with
+// "" for unnamed ones and for the whole match at index 0.
+func (pcre *PCRE) NameTable() []string {
+	names, err := pcre.names()
+	if err != nil {
+		log.Panicf("pcre_fullinfo: %v", err)
+	}
+	return names
+}
+
+func (pcre *PCRE) names() ([]string, error) {
+	captureCount, err := pcre.infoInt(InfoCaptureCount)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, captureCount+1)
+
+	table, err := pcre.nameTable()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range table {
+		names[entry.number] = entry.name
+	}
+	return names, nil
+}
+
+type nameEntry struct {
+	number int
+	name   string
+}
+
+// nameTable decodes the name table, whose entries hold a two byte group
+// number followed by the NUL terminated name, padded to the longest name.
+func (pcre *PCRE) nameTable() ([]nameEntry, error) {
+	nameCount, err := pcre.infoInt(InfoNameCount)
+	if err != nil || nameCount == 0 {
+		return nil, err
+	}
+	entrySize, err := pcre.infoInt(InfoNameEntrySize)
+	if err != nil {
+		return nil, err
+	}
+
+	var tablePtr *byte
+	if err := pcre.fullinfo(nil, InfoNameTable, unsafe.Pointer(&tablePtr)); err != nil {
+		return nil, err
+	}
+	data := unsafe.Slice(tablePtr, nameCount*entrySize)
+
+	table := make([]nameEntry, nameCount)
+	for i := range table {
+		entry := data[i*entrySize : (i+1)*entrySize]
+		name := entry[2:]
+		if end := bytes.IndexByte(name, 0); end >= 0 {
+			name = name[:end]
+		}
+		table[i] = nameEntry{number: int(entry[0])<<8 | int(entry[1]), name: string(name)}
+	}
+	return table, nil
+}
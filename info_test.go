@@ -0,0 +1,118 @@
+package pcre
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInfo(t *testing.T) {
+	re := mustCompile(t, `(?<year>\d{4})-(?<month>\d\d)(?<=-\d\d)-(\d\d)\r?`, Caseless)
+
+	info, err := re.Info(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Options&Caseless == 0 {
+		t.Errorf("Options %v do not contain Caseless", info.Options)
+	}
+	if info.CaptureCount != 3 || info.BackrefMax != 0 {
+		t.Errorf("CaptureCount %d, BackrefMax %d, want 3, 0", info.CaptureCount, info.BackrefMax)
+	}
+	if want := []string{"", "year", "month", ""}; info.NameCount != 2 || !reflect.DeepEqual(info.Names, want) {
+		t.Errorf("NameCount %d, Names %q, want 2, %q", info.NameCount, info.Names, want)
+	}
+	if info.MaxLookBehind != 3 {
+		t.Errorf("MaxLookBehind = %d, want 3", info.MaxLookBehind)
+	}
+	if !info.HasCRorLF || info.JChanged {
+		t.Errorf("HasCRorLF %v, JChanged %v, want true, false", info.HasCRorLF, info.JChanged)
+	}
+	if info.Size <= 0 {
+		t.Errorf("Size = %d", info.Size)
+	}
+
+	backref := mustCompile(t, `(?J)(?<x>a)(?<x>b)\2`, 0)
+	if info, err = backref.Info(nil); err != nil {
+		t.Fatal(err)
+	}
+	if info.BackrefMax != 2 || !info.JChanged {
+		t.Errorf("BackrefMax %d, JChanged %v, want 2, true", info.BackrefMax, info.JChanged)
+	}
+}
+
+func TestInfoStudied(t *testing.T) {
+	re := mustCompile(t, `a\d{3}`, 0)
+	extra, err := Study(re, StudyJITCompile, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer extra.Free()
+
+	info, err := re.Info(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.MinLength != 4 {
+		t.Errorf("MinLength = %d, want 4", info.MinLength)
+	}
+	if info.JIT != (info.JITSize > 0) || info.JIT != extra.UsesJIT(0) {
+		t.Errorf("JIT %v, JITSize %d, UsesJIT %v disagree", info.JIT, info.JITSize, extra.UsesJIT(0))
+	}
+}
+
+func TestInfoFirstByte(t *testing.T) {
+	for _, test := range []struct {
+		expr        string
+		options     CompileOption
+		firstByte   int
+		lastLiteral int
+	}{
+		{`(cat|cow)`, 0, 'c', -1},
+		{`^a`, Multiline, -1, 'a'},
+		{`.*x`, 0, -1, 'x'},
+		{`^a`, 0, -2, -1},
+		{`\d+`, 0, -2, -1},
+	} {
+		info, err := mustCompile(t, test.expr, test.options).Info(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.FirstByte != test.firstByte || info.LastLiteral != test.lastLiteral {
+			t.Errorf("%s with %v: FirstByte %d, LastLiteral %d, want %d, %d",
+				test.expr, test.options, info.FirstByte, info.LastLiteral, test.firstByte, test.lastLiteral)
+		}
+	}
+}
+
+func TestInfoFirstTable(t *testing.T) {
+	re := mustCompile(t, `[ab]x|cy`, 0)
+	extra, err := Study(re, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer extra.Free()
+
+	info, err := re.Info(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.FirstTable) != 32 {
+		t.Fatalf("FirstTable has %d bytes, want 32", len(info.FirstTable))
+	}
+	for c := 0; c < 256; c++ {
+		set := info.FirstTable[c/8]&(1<<(c%8)) != 0
+		if set != (c == 'a' || c == 'b' || c == 'c') {
+			t.Errorf("FirstTable has %q set to %v", rune(c), set)
+		}
+	}
+	if info.MinLength != 2 || info.MatchEmpty || !info.OkPartial {
+		t.Errorf("MinLength %d, MatchEmpty %v, OkPartial %v, want 2, false, true", info.MinLength, info.MatchEmpty, info.OkPartial)
+	}
+
+	if info, err = mustCompile(t, `a*`, 0).Info(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !info.MatchEmpty {
+		t.Error("MatchEmpty = false for a*")
+	}
+}
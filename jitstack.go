@@ -0,0 +1,63 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+//
+// // The JIT stack of the current Exec call. It is thread local because a cgo
+// // call runs on a single thread from start to end, which lets concurrent
+// // calls share a pcre_extra and still each use their own stack.
+// static __thread pcre_jit_stack *call_jit_stack;
+//
+// static pcre_jit_stack *jit_stack_callback(void *data) {
+//     if (call_jit_stack != NULL) {
+//         return call_jit_stack;
+//     }
+//     return data;
+// }
+//
+// void assign_jit_stack(pcre_extra *extra, pcre_jit_stack *stack) {
+//     pcre_assign_jit_stack(extra, jit_stack_callback, stack);
+// }
+//
+// int go_pcre_exec(const pcre *code, const pcre_extra *extra, const char *subject, int length, int startOffset, int options, int *oVector, int oVectorSize, pcre_jit_stack *stack) {
+//     int rc;
+//
+//     call_jit_stack = stack;
+//     rc = pcre_exec(code, extra, subject, length, startOffset, options, oVector, oVectorSize);
+//     call_jit_stack = NULL;
+//     return rc;
+// }
+import "C"
+
+import "unsafe"
+
+// JITStack is a stack for running JIT compiled patterns on. Without one, the
+// JIT uses 32K of machine stack, and deeply backtracking patterns or large
+// subjects fail with ErrJITStackLimit.
+type JITStack C.struct_real_pcre_jit_stack
+
+// NewJITStack allocates a JIT stack that starts at startSize bytes and may
+// grow up to maxSize bytes. Release it with Free.
+func NewJITStack(startSize, maxSize int) (*JITStack, error) {
+	stack := C.pcre_jit_stack_alloc(C.int(startSize), C.int(maxSize))
+	if stack == nil {
+		return nil, ErrNoMemory
+	}
+	return (*JITStack)(unsafe.Pointer(stack)), nil
+}
+
+func (stack *JITStack) Free() { C.pcre_jit_stack_free(stack.ptr()) }
+
+func (stack *JITStack) ptr() *C.pcre_jit_stack { return (*C.pcre_jit_stack)(unsafe.Pointer(stack)) }
+
+// AssignJITStack makes every Exec with pcreExtra run on stack, unless the
+// call brings its own through a MatchContext. A stack must not be used by
+// two matches at once, so an extra shared between goroutines should use
+// per call stacks instead. A nil stack restores the default 32K stack.
+//
+// Assigning is a no-op for patterns that were not JIT compiled.
+func (pcreExtra *PCREExtra) AssignJITStack(stack *JITStack) {
+	C.assign_jit_stack((*C.pcre_extra)(unsafe.Pointer(pcreExtra)), stack.ptr())
+}
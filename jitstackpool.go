@@ -0,0 +1,53 @@
+package pcre
+
+import "sync"
+
+// JITStackPool hands out JIT stacks for use by one match at a time, so that
+// concurrent goroutines matching the same pattern never share a stack. Get a
+// stack before a match, pass it in the MatchContext and Put it back after.
+type JITStackPool struct {
+	startSize, maxSize int
+
+	mu   sync.Mutex
+	idle []*JITStack
+}
+
+// NewJITStackPool returns a pool of stacks allocated by NewJITStack with the
+// given sizes.
+func NewJITStackPool(startSize, maxSize int) *JITStackPool {
+	return &JITStackPool{startSize: startSize, maxSize: maxSize}
+}
+
+// Get returns an idle stack from the pool, or allocates a new one.
+func (pool *JITStackPool) Get() (*JITStack, error) {
+	pool.mu.Lock()
+	if n := len(pool.idle); n > 0 {
+		stack := pool.idle[n-1]
+		pool.idle = pool.idle[:n-1]
+		pool.mu.Unlock()
+		return stack, nil
+	}
+	pool.mu.Unlock()
+
+	return NewJITStack(pool.startSize, pool.maxSize)
+}
+
+// Put returns a stack obtained from Get to the pool.
+func (pool *JITStackPool) Put(stack *JITStack) {
+	pool.mu.Lock()
+	pool.idle = append(pool.idle, stack)
+	pool.mu.Unlock()
+}
+
+// Free releases all idle stacks. Stacks handed out by Get and not yet put
+// back are not affected.
+func (pool *JITStackPool) Free() {
+	pool.mu.Lock()
+	idle := pool.idle
+	pool.idle = nil
+	pool.mu.Unlock()
+
+	for _, stack := range idle {
+		stack.Free()
+	}
+}
@@ -0,0 +1,32 @@
+package pcre
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// allocated holds the patterns and extras that were created and not freed
+// yet, which turns a second Free into a no-op instead of a double free that
+// corrupts the C heap.
+var allocated = struct {
+	sync.Mutex
+	m map[unsafe.Pointer]struct{}
+}{m: make(map[unsafe.Pointer]struct{})}
+
+func track(ptr unsafe.Pointer) {
+	allocated.Lock()
+	allocated.m[ptr] = struct{}{}
+	allocated.Unlock()
+}
+
+// untrack reports whether ptr was allocated and not freed yet, and forgets
+// it.
+func untrack(ptr unsafe.Pointer) bool {
+	allocated.Lock()
+	defer allocated.Unlock()
+	if _, ok := allocated.m[ptr]; !ok {
+		return false
+	}
+	delete(allocated.m, ptr)
+	return true
+}
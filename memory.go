@@ -0,0 +1,158 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// static int64_t mem_live, mem_peak, mem_limit;
+// static int mem_tracking;
+//
+// // Set when an allocation on this thread failed for exceeding mem_limit.
+// static __thread int mem_limit_hit;
+//
+// // Every block starts with the number of bytes counted for it in mem_live,
+// // which is 0 for blocks allocated before track_memory. The header is large
+// // enough to keep the block suitably aligned.
+// #define MEM_HEADER 16
+//
+// static void *counting_malloc(size_t size) {
+//     int64_t counted = 0, live, limit, peak;
+//     char *block;
+//
+//     if (__atomic_load_n(&mem_tracking, __ATOMIC_RELAXED)) {
+//         counted = (int64_t)size;
+//         live = __atomic_add_fetch(&mem_live, counted, __ATOMIC_RELAXED);
+//         limit = __atomic_load_n(&mem_limit, __ATOMIC_RELAXED);
+//         if (limit > 0 && live > limit) {
+//             __atomic_sub_fetch(&mem_live, counted, __ATOMIC_RELAXED);
+//             mem_limit_hit = 1;
+//             return NULL;
+//         }
+//         peak = __atomic_load_n(&mem_peak, __ATOMIC_RELAXED);
+//         while (live > peak && !__atomic_compare_exchange_n(&mem_peak, &peak, live, 1, __ATOMIC_RELAXED, __ATOMIC_RELAXED)) {
+//         }
+//     }
+//     block = malloc(MEM_HEADER + size);
+//     if (block == NULL) {
+//         __atomic_sub_fetch(&mem_live, counted, __ATOMIC_RELAXED);
+//         return NULL;
+//     }
+//     *(int64_t *)block = counted;
+//     return block + MEM_HEADER;
+// }
+//
+// static void counting_free(void *ptr) {
+//     char *block;
+//
+//     if (ptr == NULL) {
+//         return;
+//     }
+//     block = (char *)ptr - MEM_HEADER;
+//     __atomic_sub_fetch(&mem_live, *(int64_t *)block, __ATOMIC_RELAXED);
+//     free(block);
+// }
+//
+// // The allocators are installed before any Go code runs, so that every
+// // block libpcre frees carries the header, and they never change while
+// // patterns are compiled or matched on other threads. track_memory only
+// // turns on the counting.
+// __attribute__((constructor)) static void install_memory_hooks(void) {
+//     pcre_malloc = counting_malloc;
+//     pcre_free = counting_free;
+//     pcre_stack_malloc = counting_malloc;
+//     pcre_stack_free = counting_free;
+// }
+//
+// static void track_memory(void) {
+//     __atomic_store_n(&mem_tracking, 1, __ATOMIC_RELAXED);
+// }
+//
+// static void mem_stats(int64_t *live, int64_t *peak, int64_t *limit) {
+//     *live = __atomic_load_n(&mem_live, __ATOMIC_RELAXED);
+//     *peak = __atomic_load_n(&mem_peak, __ATOMIC_RELAXED);
+//     *limit = __atomic_load_n(&mem_limit, __ATOMIC_RELAXED);
+// }
+//
+// static void set_mem_limit(int64_t limit) {
+//     __atomic_store_n(&mem_limit, limit, __ATOMIC_RELAXED);
+// }
+//
+// static void reset_mem_peak(void) {
+//     __atomic_store_n(&mem_peak, __atomic_load_n(&mem_live, __ATOMIC_RELAXED), __ATOMIC_RELAXED);
+// }
+//
+// // pcre_compile2 that reports whether it failed because of mem_limit.
+// pcre *go_pcre_compile(const char *pattern, int options, int *errCode, const char **errPtr, int *errOffset, const unsigned char *tables, int *limitHit) {
+//     pcre *code;
+//
+//     mem_limit_hit = 0;
+//     code = pcre_compile2(pattern, options, errCode, errPtr, errOffset, tables);
+//     *limitHit = mem_limit_hit;
+//     return code;
+// }
+import "C"
+
+import "errors"
+
+// ErrMemoryLimit is returned by Compile when compiling the pattern would
+// take libpcre over the limit set with SetMemoryLimit.
+var ErrMemoryLimit = errors.New("pcre: memory limit exceeded")
+
+// TrackMemory starts counting the memory libpcre allocates through
+// pcre_malloc and pcre_stack_malloc, see ReadMemStats. This memory is not
+// seen by the Go heap profiler. The package installs its allocators when the
+// program starts, TrackMemory only switches the counting on, so it is safe
+// to call while other goroutines compile and match.
+//
+// Memory allocated before the switch, such as that of patterns compiled
+// for package level variables, is not counted, so call TrackMemory early,
+// typically from an init function of package main. Calls after the first
+// have no effect, tracking cannot be turned off again.
+func TrackMemory() {
+	C.track_memory()
+}
+
+// MemStats describes the memory libpcre allocated through pcre_malloc and
+// pcre_stack_malloc since TrackMemory was called. The machine code of JIT
+// compiled patterns is allocated separately and not included, see
+// (*PCRE).Footprint.
+type MemStats struct {
+	Live  int64 // Bytes currently allocated
+	Peak  int64 // Highest Live since TrackMemory or ResetPeak
+	Limit int64 // Limit set with SetMemoryLimit, 0 if there is none
+}
+
+// ReadMemStats returns the current memory statistics. They are all zero
+// when TrackMemory was not called.
+func ReadMemStats() MemStats {
+	var live, peak, limit C.int64_t
+	C.mem_stats(&live, &peak, &limit)
+	return MemStats{Live: int64(live), Peak: int64(peak), Limit: int64(limit)}
+}
+
+// SetMemoryLimit caps the memory libpcre may hold at bytes, a limit of 0
+// removes the cap. Allocations beyond it fail, which makes Compile return
+// ErrMemoryLimit and Exec ErrNoMemory. The limit only takes effect after
+// TrackMemory.
+func SetMemoryLimit(bytes int64) {
+	if bytes < 0 {
+		bytes = 0
+	}
+	C.set_mem_limit(C.int64_t(bytes))
+}
+
+// ResetPeak lowers the peak reported by ReadMemStats to the current usage.
+func ResetPeak() { C.reset_mem_peak() }
+
+// Footprint returns the number of bytes held by the compiled pattern, its
+// study data and its JIT compiled code. extra may be nil.
+func (pcre *PCRE) Footprint(extra *PCREExtra) (int, error) {
+	info, err := pcre.Info(extra)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size + info.StudySize + info.JITSize, nil
+}
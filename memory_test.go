@@ -0,0 +1,44 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+import "testing"
+
+func TestTrackMemory(t *testing.T) {
+	// Compiled before tracking starts, like a package level pattern.
+	before, err := Compile(`(a|b)+c`, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beforeExtra, err := Study(before, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	TrackMemory()
+	live := ReadMemStats().Live
+
+	re := mustCompile(t, `(x|y)+z`, 0)
+	size, err := re.Footprint(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grown := ReadMemStats().Live - live; grown < int64(size) {
+		t.Errorf("Live grew by %d compiling a pattern of %d bytes", grown, size)
+	}
+	re.Free()
+	if now := ReadMemStats().Live; now != live {
+		t.Errorf("Live = %d after freeing the pattern, want %d", now, live)
+	}
+
+	// Freeing the untracked blocks neither counts them nor corrupts the heap.
+	if e := before.Exec(beforeExtra, "abac", 0, 0, nil); e < 0 {
+		t.Errorf("Exec of a pattern compiled before TrackMemory = %v", e)
+	}
+	beforeExtra.Free()
+	before.Free()
+	if now := ReadMemStats().Live; now != live {
+		t.Errorf("Live = %d after freeing a pattern compiled before TrackMemory, want %d", now, live)
+	}
+}
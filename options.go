@@ -0,0 +1,90 @@
+package pcre
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompileOption holds options for Compile, see the constants of the same
+// type. Options that libpcre also accepts when matching, such as Anchored,
+// are untyped so that they can be used as either kind.
+type CompileOption int64
+
+// ExecOption holds options for Exec and the other matching functions.
+type ExecOption int64
+
+// StudyOption holds options for Study.
+type StudyOption int
+
+// jitMode returns the StudyJIT option a pattern must be studied with for a
+// match with options to run on the JIT. PartialHard wins over PartialSoft.
+func jitMode(options ExecOption) StudyOption {
+	switch {
+	case options&PartialHard != 0:
+		return StudyJITPartialHardCompile
+	case options&PartialSoft != 0:
+		return StudyJITPartialSoftCompile
+	}
+	return StudyJITCompile
+}
+
+// optionName names the option value, a field of the bits in mask, or a
+// single flag when mask is 0.
+type optionName struct {
+	name  string
+	value int64
+	mask  int64
+}
+
+// formatOptions returns the names of the options set in o separated by |,
+// with any bits left unnamed in hexadecimal.
+func formatOptions(o int64, names []optionName) string {
+	if o == 0 {
+		return "0"
+	}
+	var parts []string
+	for _, n := range names {
+		mask := n.mask
+		if mask == 0 {
+			mask = n.value
+		}
+		if o&mask == n.value {
+			parts = append(parts, n.name)
+			o &^= mask
+		}
+	}
+	if o != 0 {
+		parts = append(parts, fmt.Sprintf("%#x", o))
+	}
+	return strings.Join(parts, "|")
+}
+
+func (o CompileOption) String() string { return formatOptions(int64(o), compileOptionNames) }
+func (o ExecOption) String() string    { return formatOptions(int64(o), execOptionNames) }
+func (o StudyOption) String() string   { return formatOptions(int64(o), studyOptionNames) }
+
+// optionLetters maps the letters of Perl-style flags to compile options.
+var optionLetters = map[rune]CompileOption{
+	'i': Caseless,
+	'm': Multiline,
+	's': DotAll,
+	'x': Extended,
+	'n': NoAutoCapture,
+	'U': UnGreedy,
+	'J': DupNames,
+}
+
+// ParseCompileOptions returns the compile options named by the Perl-style
+// letters in flags, as in (?imsx) inside a pattern: i Caseless, m Multiline,
+// s DotAll, x Extended, n NoAutoCapture, U UnGreedy and J DupNames.
+func ParseCompileOptions(flags string) (CompileOption, error) {
+	var options CompileOption
+	for _, r := range flags {
+		option, ok := optionLetters[r]
+		if !ok {
+			return 0, fmt.Errorf("pcre: unknown option letter %q in %q", r, flags)
+		}
+		options |= option
+	}
+	return options, nil
+}
@@ -0,0 +1,67 @@
+package pcre
+
+// MatchResult tells a full match, a partial match and no match apart.
+type MatchResult int
+
+const (
+	MatchNone    MatchResult = iota // The subject cannot match, however it continues
+	MatchFull                       // The subject matches
+	MatchPartial                    // The subject ended in the middle of a possible match
+)
+
+func (r MatchResult) String() string {
+	switch r {
+	case MatchNone:
+		return "no match"
+	case MatchFull:
+		return "full match"
+	case MatchPartial:
+		return "partial match"
+	}
+	return "invalid MatchResult"
+}
+
+// PartialResult is the outcome of ExecPartial.
+type PartialResult struct {
+	Result MatchResult
+
+	// Start and End delimit the match, or for a partial match the text from
+	// where the partial match begins up to the end of the subject.
+	Start, End int
+
+	// Keep is the earliest offset the partial match depends on, which lies
+	// before Start when the pattern has lookbehinds. When more input
+	// arrives, the subject can be trimmed to start at Keep before matching
+	// again.
+	Keep int
+}
+
+// ExecPartial is like ExecContext, but reports partial matches as a result
+// instead of as ErrPartial. options must contain PartialSoft, where a full
+// match takes precedence, or PartialHard, where a partial match does; if it
+// contains neither PartialSoft is used. The JIT is only used for patterns
+// studied with the matching StudyJITPartialSoftCompile or
+// StudyJITPartialHardCompile option.
+func (pcre *PCRE) ExecPartial(extra *PCREExtra, ctx *MatchContext, subject string, startOffset int, options ExecOption, oVector []int) (PartialResult, error) {
+	if options&(PartialSoft|PartialHard) == 0 {
+		options |= PartialSoft
+	}
+	if len(oVector) < 3 {
+		oVector = make([]int, 3)
+	}
+
+	switch e := pcre.ExecContext(extra, ctx, subject, startOffset, options, oVector); {
+	case e >= 0:
+		return PartialResult{Result: MatchFull, Start: oVector[0], End: oVector[1], Keep: oVector[0]}, nil
+	case e == ErrNoMatch:
+		return PartialResult{Result: MatchNone}, nil
+	case e == ErrPartial:
+		start, keep, err := pcre.partialStart(extra, subject, oVector)
+		if err != nil {
+			return PartialResult{}, err
+		}
+		return PartialResult{Result: MatchPartial, Start: start, End: oVector[1], Keep: keep}, nil
+	default:
+		return PartialResult{}, e.Detail(oVector)
+	}
+}
@@ -0,0 +1,26 @@
+package pcre
+
+import "testing"
+
+func TestExecPartialLookbehind(t *testing.T) {
+	re := mustCompile(t, `(?<=abc)123`, 0)
+	for _, options := range []ExecOption{PartialSoft, PartialHard} {
+		partial, err := re.ExecPartial(nil, nil, "xxabc12", 0, options, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := PartialResult{Result: MatchPartial, Start: 5, End: 7, Keep: 2}
+		if partial != want {
+			t.Errorf("ExecPartial with options %#x = %+v, want %+v", options, partial, want)
+		}
+	}
+
+	re = mustCompile(t, `(?<=ü)12`, UTF8)
+	partial, err := re.ExecPartial(nil, nil, "xü1", 0, PartialSoft, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (PartialResult{Result: MatchPartial, Start: 3, End: 4, Keep: 1}); partial != want {
+		t.Errorf("ExecPartial in UTF-8 mode = %+v, want %+v", partial, want)
+	}
+}
@@ -0,0 +1,118 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+import "C"
+
+import "unsafe"
+
+// Info returns the information pcre_fullinfo has on pcre. The study related
+// fields are only filled in when extra is non-nil.
+func (pcre *PCRE) Info(extra *PCREExtra) (*PatternInfo, error) {
+	var (
+		options                               C.ulong
+		size, studySize, jitSize              C.size_t
+		captureCount, backrefMax, firstByte   C.int
+		lastLiteral, nameEntrySize, nameCount C.int
+		minLength, maxLookBehind, matchEmpty  C.int
+		okPartial, jChanged, hasCRorLF, jit   C.int
+		firstTable                            *C.uchar
+	)
+
+	for _, query := range []struct {
+		what  Info
+		where unsafe.Pointer
+	}{
+		{InfoOptions, unsafe.Pointer(&options)},
+		{InfoSize, unsafe.Pointer(&size)},
+		{InfoCaptureCount, unsafe.Pointer(&captureCount)},
+		{InfoBackrefMax, unsafe.Pointer(&backrefMax)},
+		{InfoFirstByte, unsafe.Pointer(&firstByte)},
+		{InfoFirstTable, unsafe.Pointer(&firstTable)},
+		{InfoLastLiteral, unsafe.Pointer(&lastLiteral)},
+		{InfoNameEntrySize, unsafe.Pointer(&nameEntrySize)},
+		{InfoNameCount, unsafe.Pointer(&nameCount)},
+		{InfoStudySize, unsafe.Pointer(&studySize)},
+		{InfoMinLength, unsafe.Pointer(&minLength)},
+		{InfoMaxLookBehind, unsafe.Pointer(&maxLookBehind)},
+		{InfoMatchEmpty, unsafe.Pointer(&matchEmpty)},
+		{InfoOkPartial, unsafe.Pointer(&okPartial)},
+		{InfoJchanged, unsafe.Pointer(&jChanged)},
+		{InfoHasCRorLF, unsafe.Pointer(&hasCRorLF)},
+		{InfoJIT, unsafe.Pointer(&jit)},
+		{InfoJITSize, unsafe.Pointer(&jitSize)},
+	} {
+		if err := pcre.fullinfo(extra, query.what, query.where); err != nil {
+			return nil, err
+		}
+	}
+
+	names, err := pcre.names()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PatternInfo{
+		Options:       CompileOption(options),
+		Size:          int(size),
+		CaptureCount:  int(captureCount),
+		BackrefMax:    int(backrefMax),
+		FirstByte:     int(firstByte),
+		LastLiteral:   int(lastLiteral),
+		NameEntrySize: int(nameEntrySize),
+		NameCount:     int(nameCount),
+		Names:         names,
+		StudySize:     int(studySize),
+		MinLength:     int(minLength),
+		MaxLookBehind: int(maxLookBehind),
+		MatchEmpty:    matchEmpty != 0,
+		OkPartial:     okPartial != 0,
+		JChanged:      jChanged != 0,
+		HasCRorLF:     hasCRorLF != 0,
+		JIT:           jit != 0,
+		JITSize:       int(jitSize),
+	}
+	if firstTable != nil {
+		info.FirstTable = C.GoBytes(unsafe.Pointer(firstTable), 256/8)
+	}
+	return info, nil
+}
+
+// StringNumber returns the number of the subpattern called name. When
+// several subpatterns share the name, which DupNames allows, any one of them
+// may be returned; use StringNumbers to get all. It returns ErrNoSubstring
+// if there is no subpattern called name.
+func (pcre *PCRE) StringNumber(name string) (int, error) {
+	nameCStr := C.CString(name)
+	defer C.free(unsafe.Pointer(nameCStr))
+
+	n := C.pcre_get_stringnumber((*C.pcre)(unsafe.Pointer(pcre)), nameCStr)
+	if n < 0 {
+		return 0, Error(n)
+	}
+	return int(n), nil
+}
+
+// StringNumbers returns the numbers of all subpatterns called name, in
+// increasing order. It returns ErrNoSubstring if there are none.
+func (pcre *PCRE) StringNumbers(name string) ([]int, error) {
+	nameCStr := C.CString(name)
+	defer C.free(unsafe.Pointer(nameCStr))
+
+	var first, last *C.char
+	entrySize := C.pcre_get_stringtable_entries((*C.pcre)(unsafe.Pointer(pcre)), nameCStr, &first, &last)
+	if entrySize < 0 {
+		return nil, Error(entrySize)
+	}
+
+	count := int((uintptr(unsafe.Pointer(last))-uintptr(unsafe.Pointer(first)))/uintptr(entrySize)) + 1
+	entries := unsafe.Slice((*byte)(unsafe.Pointer(first)), count*int(entrySize))
+	numbers := make([]int, count)
+	for i := range numbers {
+		entry := entries[i*int(entrySize):]
+		numbers[i] = int(entry[0])<<8 | int(entry[1])
+	}
+	return numbers, nil
+}
@@ -0,0 +1,30 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+import "testing"
+
+func TestInfoStudyData(t *testing.T) {
+	re := mustCompile(t, `[ab]x|cy`, 0)
+
+	info, err := re.Info(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.FirstTable != nil || info.MinLength != -1 {
+		t.Errorf("without study data: FirstTable %v, MinLength %d, want nil, -1", info.FirstTable, info.MinLength)
+	}
+
+	extra, err := Study(re, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer extra.Free()
+	if info, err = re.Info(extra); err != nil {
+		t.Fatal(err)
+	}
+	if info.MinLength != 2 || info.StudySize <= 0 {
+		t.Errorf("MinLength %d, StudySize %d, want 2 and positive", info.MinLength, info.StudySize)
+	}
+}
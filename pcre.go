@@ -1,3 +1,6 @@
+//go:build !pcre2
+// +build !pcre2
+
 package pcre
 
 // #cgo LDFLAGS: -lpcre
@@ -8,62 +11,182 @@ package pcre
 //     pcre_free(ptr);
 // }
 //
+// pcre *go_pcre_compile(const char *pattern, int options, int *errCode, const char **errPtr, int *errOffset, const unsigned char *tables, int *limitHit);
+//
+// int go_pcre_exec(const pcre *code, const pcre_extra *extra, const char *subject, int length, int startOffset, int options, int *oVector, int oVectorSize, pcre_jit_stack *stack);
+//
 import "C"
 
 import (
-	"errors"
+	"fmt"
+	"sync"
 	"unsafe"
 )
 
 const (
-	Major  = C.PCRE_MAJOR
-	Minor     = C.PCRE_MINOR
-	Date      = C.PCRE_DATE
+	Major = C.PCRE_MAJOR
+	Minor = C.PCRE_MINOR
+	Date  = C.PCRE_DATE
+)
+
+const (
+	StudyJITCompile            StudyOption = C.PCRE_STUDY_JIT_COMPILE
+	StudyJITPartialSoftCompile StudyOption = C.PCRE_STUDY_JIT_PARTIAL_SOFT_COMPILE // JIT for matching with PartialSoft
+	StudyJITPartialHardCompile StudyOption = C.PCRE_STUDY_JIT_PARTIAL_HARD_COMPILE // JIT for matching with PartialHard
 )
 
-const StudyJITCompile = C.PCRE_STUDY_JIT_COMPILE
+// jitExecOptions are the Exec options the JIT can handle, pcre_exec falls
+// back to the interpreter when any other option is given.
+const jitExecOptions = NoUTF8Check | NotBOL | NotEOL | NotEmpty | NotEmptyAtStart | PartialSoft | PartialHard
+
+// UsesJIT reports whether Exec, called with pcreExtra and options, runs on
+// JIT compiled code instead of the interpreter. Partial matching additionally
+// requires that the pattern was studied for the same partial mode.
+func (pcreExtra *PCREExtra) UsesJIT(options ExecOption) bool {
+	if pcreExtra == nil || pcreExtra.executable_jit == nil {
+		return false
+	}
+	if pcreExtra.flags&(C.PCRE_EXTRA_EXECUTABLE_JIT|C.PCRE_EXTRA_TABLES) != C.PCRE_EXTRA_EXECUTABLE_JIT {
+		return false
+	}
+	if options&^jitExecOptions != 0 {
+		return false
+	}
+	jitModes.RLock()
+	modes := jitModes.m[pcreExtra]
+	jitModes.RUnlock()
+	return modes&jitMode(options) != 0
+}
 
-type Option int
+// jitModes holds the StudyJIT options every JIT compiled PCREExtra was
+// studied with, which pcre_extra has no room for, until it is freed.
+var jitModes = struct {
+	sync.RWMutex
+	m map[*PCREExtra]StudyOption
+}{m: make(map[*PCREExtra]StudyOption)}
+
+func holdJITModes(extra *PCREExtra, options StudyOption) {
+	jitModes.Lock()
+	jitModes.m[extra] = options & (StudyJITCompile | StudyJITPartialSoftCompile | StudyJITPartialHardCompile)
+	jitModes.Unlock()
+}
+
+func releaseJITModes(extra *PCREExtra) {
+	jitModes.Lock()
+	delete(jitModes.m, extra)
+	jitModes.Unlock()
+}
+
+// Options for Compile.
+const (
+	Caseless         CompileOption = C.PCRE_CASELESS
+	Multiline        CompileOption = C.PCRE_MULTILINE
+	DotAll           CompileOption = C.PCRE_DOTALL
+	Extended         CompileOption = C.PCRE_EXTENDED
+	DollarEndOnly    CompileOption = C.PCRE_DOLLAR_ENDONLY
+	Extra            CompileOption = C.PCRE_EXTRA
+	UnGreedy         CompileOption = C.PCRE_UNGREEDY
+	UTF8             CompileOption = C.PCRE_UTF8
+	UTF16            CompileOption = C.PCRE_UTF16
+	UTF32            CompileOption = C.PCRE_UTF32
+	NoAutoCapture    CompileOption = C.PCRE_NO_AUTO_CAPTURE
+	AutoCallout      CompileOption = C.PCRE_AUTO_CALLOUT
+	FirstLine        CompileOption = C.PCRE_FIRSTLINE
+	DupNames         CompileOption = C.PCRE_DUPNAMES
+	JavascriptCompat CompileOption = C.PCRE_JAVASCRIPT_COMPAT
+	UCP              CompileOption = C.PCRE_UCP
+	NewlineCR        CompileOption = C.PCRE_NEWLINE_CR
+	NewlineLF        CompileOption = C.PCRE_NEWLINE_LF
+	NewlineCRLF      CompileOption = C.PCRE_NEWLINE_CRLF
+	NewlineAny       CompileOption = C.PCRE_NEWLINE_ANY
+	NewlineAnyCRLF   CompileOption = C.PCRE_NEWLINE_ANYCRLF
+	BSRAnyCRLF       CompileOption = C.PCRE_BSR_ANYCRLF
+	BSRUnicode       CompileOption = C.PCRE_BSR_UNICODE
+	NoStartOptimize  CompileOption = C.PCRE_NO_START_OPTIMIZE
+	NoStartOptimise  CompileOption = C.PCRE_NO_START_OPTIMISE // <3 Rule britannia
+)
+
+// Options for Exec and the other matching functions.
+const (
+	NotBOL          ExecOption = C.PCRE_NOTBOL
+	NotEOL          ExecOption = C.PCRE_NOTEOL
+	NotEmpty        ExecOption = C.PCRE_NOTEMPTY
+	PartialSoft     ExecOption = C.PCRE_PARTIAL_SOFT
+	Partial         ExecOption = C.PCRE_PARTIAL
+	DFAShortest     ExecOption = C.PCRE_DFA_SHORTEST
+	DFARestart      ExecOption = C.PCRE_DFA_RESTART
+	PartialHard     ExecOption = C.PCRE_PARTIAL_HARD
+	NotEmptyAtStart ExecOption = C.PCRE_NOTEMPTY_ATSTART
+)
 
+// Options libpcre accepts both when compiling and when matching, where they
+// override the ones the pattern was compiled with. The Newline*, BSR* and
+// NoStartOptimize options are accepted when matching too, but are typed
+// CompileOption as with libpcre2; convert them with ExecOption for that.
 const (
-	Caseless      = C.PCRE_CASELESS
-	Multiline     = C.PCRE_MULTILINE
-	DotAll        = C.PCRE_DOTALL
-	Extended      = C.PCRE_EXTENDED
-	Anchored      = C.PCRE_ANCHORED
-	DollarEndOnly = C.PCRE_DOLLAR_ENDONLY
-	Extra         = C.PCRE_EXTRA
-	NotBOL        = C.PCRE_NOTBOL
-	NotEOL        = C.PCRE_NOTEOL
-	UnGreedy         = C.PCRE_UNGREEDY
-	NotEmpty         = C.PCRE_NOTEMPTY
-	UTF8             = C.PCRE_UTF8
-	UTF16            = C.PCRE_UTF16
-	NoAutoCapture    = C.PCRE_NO_AUTO_CAPTURE
-	NoUTF8Check      = C.PCRE_NO_UTF8_CHECK
-	NoUTF16Check     = C.PCRE_NO_UTF16_CHECK
-	AutoCallout      = C.PCRE_AUTO_CALLOUT
-	PartialSoft      = C.PCRE_PARTIAL_SOFT
-	Partial          = C.PCRE_PARTIAL
-	DFAShortest      = C.PCRE_DFA_SHORTEST
-	DFARestart       = C.PCRE_DFA_RESTART
-	FirstLine        = C.PCRE_FIRSTLINE
-	DupNames         = C.PCRE_DUPNAMES
-	NewlineCR        = C.PCRE_NEWLINE_CR
-	NewlineLF        = C.PCRE_NEWLINE_LF
-	NewlineCRLF      = C.PCRE_NEWLINE_CRLF
-	NewlineAny       = C.PCRE_NEWLINE_ANY
-	NewlineAnyCRLF   = C.PCRE_NEWLINE_ANYCRLF
-	BSRAnyCRLF       = C.PCRE_BSR_ANYCRLF
-	BSRUnicode       = C.PCRE_BSR_UNICODE
-	JavascriptCompat = C.PCRE_JAVASCRIPT_COMPAT
-	NoStartOptimize  = C.PCRE_NO_START_OPTIMIZE
-	NoStartOptimise  = C.PCRE_NO_START_OPTIMISE // <3 Rule britannia
-	PartialHard      = C.PCRE_PARTIAL_HARD
-	NotEmptyAtStart  = C.PCRE_NOTEMPTY_ATSTART
-	UCP              = C.PCRE_UCP
+	Anchored     = C.PCRE_ANCHORED
+	NoUTF8Check  = C.PCRE_NO_UTF8_CHECK
+	NoUTF16Check = C.PCRE_NO_UTF16_CHECK
+	NoUTF32Check = C.PCRE_NO_UTF32_CHECK
 )
 
+// newlineMask covers the bits of the Newline* options, which are values
+// rather than flags.
+const newlineMask = C.PCRE_NEWLINE_CR | C.PCRE_NEWLINE_LF | C.PCRE_NEWLINE_ANY
+
+// The Newline* options share bits, the combined ones are listed first.
+var newlineOptionNames = []optionName{
+	{"NewlineAnyCRLF", C.PCRE_NEWLINE_ANYCRLF, newlineMask},
+	{"NewlineCRLF", C.PCRE_NEWLINE_CRLF, newlineMask},
+	{"NewlineAny", C.PCRE_NEWLINE_ANY, newlineMask},
+	{"NewlineCR", C.PCRE_NEWLINE_CR, newlineMask},
+	{"NewlineLF", C.PCRE_NEWLINE_LF, newlineMask},
+}
+
+var compileOptionNames = append([]optionName{
+	{"Caseless", C.PCRE_CASELESS, 0},
+	{"Multiline", C.PCRE_MULTILINE, 0},
+	{"DotAll", C.PCRE_DOTALL, 0},
+	{"Extended", C.PCRE_EXTENDED, 0},
+	{"Anchored", C.PCRE_ANCHORED, 0},
+	{"DollarEndOnly", C.PCRE_DOLLAR_ENDONLY, 0},
+	{"Extra", C.PCRE_EXTRA, 0},
+	{"UnGreedy", C.PCRE_UNGREEDY, 0},
+	{"UTF8", C.PCRE_UTF8, 0},
+	{"NoAutoCapture", C.PCRE_NO_AUTO_CAPTURE, 0},
+	{"NoUTF8Check", C.PCRE_NO_UTF8_CHECK, 0},
+	{"AutoCallout", C.PCRE_AUTO_CALLOUT, 0},
+	{"FirstLine", C.PCRE_FIRSTLINE, 0},
+	{"DupNames", C.PCRE_DUPNAMES, 0},
+	{"BSRAnyCRLF", C.PCRE_BSR_ANYCRLF, 0},
+	{"BSRUnicode", C.PCRE_BSR_UNICODE, 0},
+	{"JavascriptCompat", C.PCRE_JAVASCRIPT_COMPAT, 0},
+	{"NoStartOptimize", C.PCRE_NO_START_OPTIMIZE, 0},
+	{"UCP", C.PCRE_UCP, 0},
+}, newlineOptionNames...)
+
+var execOptionNames = append([]optionName{
+	{"Anchored", C.PCRE_ANCHORED, 0},
+	{"NotBOL", C.PCRE_NOTBOL, 0},
+	{"NotEOL", C.PCRE_NOTEOL, 0},
+	{"NotEmpty", C.PCRE_NOTEMPTY, 0},
+	{"NoUTF8Check", C.PCRE_NO_UTF8_CHECK, 0},
+	{"PartialSoft", C.PCRE_PARTIAL_SOFT, 0},
+	{"DFAShortest", C.PCRE_DFA_SHORTEST, 0},
+	{"DFARestart", C.PCRE_DFA_RESTART, 0},
+	{"BSRAnyCRLF", C.PCRE_BSR_ANYCRLF, 0},
+	{"BSRUnicode", C.PCRE_BSR_UNICODE, 0},
+	{"NoStartOptimize", C.PCRE_NO_START_OPTIMIZE, 0},
+	{"PartialHard", C.PCRE_PARTIAL_HARD, 0},
+	{"NotEmptyAtStart", C.PCRE_NOTEMPTY_ATSTART, 0},
+}, newlineOptionNames...)
+
+var studyOptionNames = []optionName{
+	{"StudyJITCompile", C.PCRE_STUDY_JIT_COMPILE, 0},
+	{"StudyJITPartialSoftCompile", C.PCRE_STUDY_JIT_PARTIAL_SOFT_COMPILE, 0},
+	{"StudyJITPartialHardCompile", C.PCRE_STUDY_JIT_PARTIAL_HARD_COMPILE, 0},
+}
+
 type Info int
 
 const (
@@ -87,12 +210,22 @@ const (
 	InfoJIT           = C.PCRE_INFO_JIT
 	InfoJITSize       = C.PCRE_INFO_JITSIZE
 	InfoMaxLookBehind = C.PCRE_INFO_MAXLOOKBEHIND
+	InfoMatchEmpty    = C.PCRE_INFO_MATCH_EMPTY
+	InfoAllOptions    = InfoOptions
+
+	// libpcre keeps these in the options, see PatternInfo.Options.
+	InfoArgOptions = infoUnsupported
+	InfoBSR        = infoUnsupported
+	InfoNewline    = infoUnsupported
 )
 
 type PCRE C.struct_real_pcre8_or_16
 
-func Compile(expr string, options Option, _ interface{}) (*PCRE, error) {
+// Compile compiles expr with options. A nil tables uses the character tables
+// built into libpcre.
+func Compile(expr string, options CompileOption, tables *Tables) (*PCRE, error) {
 	var (
+		errCode   C.int
 		errPtr    *C.char
 		errOffset C.int
 	)
@@ -100,80 +233,197 @@ func Compile(expr string, options Option, _ interface{}) (*PCRE, error) {
 	pattern := C.CString(expr)
 	defer C.free(unsafe.Pointer(pattern))
 
-	re := C.pcre_compile(pattern, C.int(options), &errPtr, &errOffset, nil)
+	var tablesPtr *C.uchar
+	if tables != nil {
+		tablesPtr = tables.ptr
+	}
+
+	var limitHit C.int
+	re := C.go_pcre_compile(pattern, C.int(options), &errCode, &errPtr, &errOffset, tablesPtr, &limitHit)
 	if re == nil {
-		return nil, errors.New(C.GoString(errPtr))
+		if limitHit != 0 {
+			return nil, fmt.Errorf("%w compiling `%s`", ErrMemoryLimit, expr)
+		}
+		return nil, &CompileError{
+			Message: C.GoString(errPtr),
+			Code:    int(errCode),
+			Offset:  int(errOffset),
+			Pattern: expr,
+		}
 	}
 
+	track(unsafe.Pointer(re))
+	if tables != nil {
+		holdTables((*PCRE)(re), tables)
+	}
 	return (*PCRE)(re), nil
 }
 
-type Error int
+// Exec matches subject against the compiled pattern starting at startOffset.
+// If extra is non-nil the study data it holds is used, and when the pattern
+// was JIT compiled (see UsesJIT) the match runs on the JIT.
+func (pcre *PCRE) Exec(extra *PCREExtra, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.exec((*C.pcre_extra)(unsafe.Pointer(extra)), nil, subject, startOffset, options, oVector)
+}
 
-const (
-	ErrNoMatch       = C.PCRE_ERROR_NOMATCH
-	ErrNull          = C.PCRE_ERROR_NULL
-	ErrBadOption     = C.PCRE_ERROR_BADOPTION
-	ErrBadMagic      = C.PCRE_ERROR_BADMAGIC
-	ErrUnknownOpcode = C.PCRE_ERROR_UNKNOWN_OPCODE
-)
+// ExecBytes is like Exec, but matches the bytes of subject. The subject is
+// read in place rather than copied.
+func (pcre *PCRE) ExecBytes(extra *PCREExtra, subject []byte, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.Exec(extra, bytesToString(subject), startOffset, options, oVector)
+}
 
-/*
-PCRE_ERROR_UNKNOWN_NODE
-PCRE_ERROR_NOMEMORY
-PCRE_ERROR_NOSUBSTRING
-PCRE_ERROR_MATCHLIMIT
-PCRE_ERROR_CALLOUT
-PCRE_ERROR_BADUTF8
-PCRE_ERROR_BADUTF16
-PCRE_ERROR_BADUTF8_OFFSET
-PCRE_ERROR_BADUTF16_OFFSET
-PCRE_ERROR_PARTIAL
-PCRE_ERROR_BADPARTIAL
-PCRE_ERROR_INTERNAL
-PCRE_ERROR_BADCOUNT
-PCRE_ERROR_DFA_UITEM
-PCRE_ERROR_DFA_UCOND
-PCRE_ERROR_DFA_UMLIMIT
-PCRE_ERROR_DFA_WSSIZE
-PCRE_ERROR_DFA_RECURSE
-PCRE_ERROR_RECURSIONLIMIT
-PCRE_ERROR_NULLWSLIMIT
-PCRE_ERROR_BADNEWLINE
-PCRE_ERROR_BADOFFSET
-PCRE_ERROR_SHORTUTF8
-PCRE_ERROR_SHORTUTF16
-PCRE_ERROR_RECURSELOOP
-PCRE_ERROR_JIT_STACKLIMIT
-PCRE_ERROR_BADMODE
-PCRE_ERROR_BADENDIANNESS
-PCRE_ERROR_DFA_BADRESTART
-
-// Specific error codes for UTF-8 validity checks
-
-#define PCRE_UTF8_ERR0               0
-#define PCRE_UTF8_ERR1               1
-#define PCRE_UTF8_ERR2               2
-#define PCRE_UTF8_ERR3               3
-#define PCRE_UTF8_ERR4               4
-#define PCRE_UTF8_ERR5               5
-#define PCRE_UTF8_ERR6               6
-#define PCRE_UTF8_ERR7               7
-#define PCRE_UTF8_ERR8               8
-#define PCRE_UTF8_ERR9               9
-#define PCRE_UTF8_ERR10             10
-#define PCRE_UTF8_ERR11             11
-#define PCRE_UTF8_ERR12             12
-#define PCRE_UTF8_ERR13             13
-#define PCRE_UTF8_ERR14             14
-#define PCRE_UTF8_ERR15             15
-#define PCRE_UTF8_ERR16             16
-#define PCRE_UTF8_ERR17             17
-#define PCRE_UTF8_ERR18             18
-#define PCRE_UTF8_ERR19             19
-#define PCRE_UTF8_ERR20             20
-#define PCRE_UTF8_ERR21             21
+// ExecLimits is like Exec, but applies limits to this call only. Non-zero
+// limits take precedence over those stored in extra.
+func (pcre *PCRE) ExecLimits(extra *PCREExtra, limits Limits, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.ExecContext(extra, &MatchContext{Limits: limits}, subject, startOffset, options, oVector)
+}
+
+// MatchContext holds settings for a single ExecContext call, which lets
+// concurrent calls sharing one PCREExtra each use their own.
+type MatchContext struct {
+	// Limits override the ones stored in the PCREExtra, see ExecLimits.
+	Limits Limits
+
+	// JITStack, if non-nil, is the stack a JIT compiled pattern runs on for
+	// this call. It must not be in use by another call at the same time.
+	JITStack *JITStack
+
+	// Callout, if non-nil, is called at the callout points of the pattern
+	// during this call.
+	Callout Callout
 
+	// Mark, if non-nil, receives the name of the last (*MARK), (*PRUNE) or
+	// (*THEN) passed on the matching path, or "" if there was none. When
+	// the match fails it receives the last name encountered.
+	Mark *string
+}
+
+// ExecContext is like Exec, but applies the settings in ctx to this call.
+// A nil ctx behaves like Exec.
+func (pcre *PCRE) ExecContext(extra *PCREExtra, ctx *MatchContext, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	callExtra := (*C.pcre_extra)(unsafe.Pointer(extra))
+	if ctx == nil {
+		return pcre.exec(callExtra, nil, subject, startOffset, options, oVector)
+	}
+
+	if ctx.Limits != (Limits{}) || ctx.Callout != nil || ctx.Mark != nil {
+		callExtra = localExtra(extra)
+		defer extraPool.Put(callExtra)
+		ctx.Limits.apply(callExtra)
+	}
+	if ctx.Callout != nil {
+		done := setCallout(callExtra, ctx.Callout, subject, len(oVector))
+		defer done()
+	}
+	if ctx.Mark == nil {
+		return pcre.exec(callExtra, ctx.JITStack, subject, startOffset, options, oVector)
+	}
+
+	// The mark is returned as a pointer into the compiled pattern, which
+	// pcre_exec stores in C memory since callExtra may live in Go memory.
+	mark := (**C.uchar)(C.malloc(C.size_t(unsafe.Sizeof((*C.uchar)(nil)))))
+	defer C.free(unsafe.Pointer(mark))
+	*mark = nil
+	callExtra.flags |= C.PCRE_EXTRA_MARK
+	callExtra.mark = mark
+
+	e := pcre.exec(callExtra, ctx.JITStack, subject, startOffset, options, oVector)
+	*ctx.Mark = ""
+	if *mark != nil {
+		*ctx.Mark = C.GoString((*C.char)(unsafe.Pointer(*mark)))
+	}
+	return e
+}
+
+// ExecContextBytes is like ExecContext, but matches the bytes of subject,
+// which are read in place. A Callout sees them through CalloutBlock.Subject,
+// which must not be retained after the callout returns.
+func (pcre *PCRE) ExecContextBytes(extra *PCREExtra, ctx *MatchContext, subject []byte, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.ExecContext(extra, ctx, bytesToString(subject), startOffset, options, oVector)
+}
+
+// extraPool holds the copies of PCREExtra that ExecContext adjusts for a
+// single call.
+var extraPool = sync.Pool{
+	New: func() interface{} { return new(C.pcre_extra) },
+}
+
+// localExtra returns a copy of extra from extraPool, which the settings of a
+// single call can be applied to. Put it back once the call returned.
+func localExtra(extra *PCREExtra) *C.pcre_extra {
+	local := extraPool.Get().(*C.pcre_extra)
+	*local = C.pcre_extra{}
+	if extra != nil {
+		*local = *(*C.pcre_extra)(unsafe.Pointer(extra))
+	}
+	return local
+}
+
+func (pcre *PCRE) exec(extra *C.pcre_extra, stack *JITStack, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.call(false, extra, stack, subject, startOffset, options, oVector)
+}
+
+// partialStart returns where a partial match in oVector begins and the
+// earliest offset it inspected. pcre_exec stores the latter, which includes
+// any lookbehind, in the first slot and the start of the match in the third.
+func (pcre *PCRE) partialStart(_ *PCREExtra, _ string, oVector []int) (start, keep int, err error) {
+	return oVector[2], oVector[0], nil
+}
+
+// ExecJIT is like Exec, but runs the JIT compiled code of the pattern
+// directly with pcre_jit_exec, which skips the checks pcre_exec makes on
+// every call. It fails with ErrJITBadOption unless extra.UsesJIT(options),
+// and with ErrBadOffset if startOffset is not within subject.
+// The subject is not checked for valid UTF-8, matching an invalid one is
+// undefined. stack is the JIT stack to run on, nil uses 32K of machine
+// stack; a stack assigned with AssignJITStack is not used. The match limit
+// stored in extra applies.
+func (pcre *PCRE) ExecJIT(extra *PCREExtra, stack *JITStack, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	if !extra.UsesJIT(options) {
+		return ErrJITBadOption
+	}
+	// The JIT does not check the offset, one outside subject would have it
+	// read out of bounds.
+	if startOffset < 0 || startOffset > len(subject) {
+		return ErrBadOffset
+	}
+	return pcre.call(true, (*C.pcre_extra)(unsafe.Pointer(extra)), stack, subject, startOffset, options, oVector)
+}
+
+// call runs pcre_exec, or pcre_jit_exec if jit is set, converting the
+// offsets between the Go and C vectors.
+func (pcre *PCRE) call(jit bool, extra *C.pcre_extra, stack *JITStack, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	oVectorC := getOVector(len(oVector))
+	defer putOVector(oVectorC)
+
+	var oVectorPtr *C.int
+	if len(oVector) > 0 {
+		oVectorPtr = &(*oVectorC)[0]
+	}
+
+	var r C.int
+	if jit {
+		r = C.pcre_jit_exec((*C.pcre)(unsafe.Pointer(pcre)), extra, subjectPtr(subject), C.int(len(subject)), C.int(startOffset), C.int(options), oVectorPtr, C.int(len(oVector)), stack.ptr())
+	} else {
+		r = C.go_pcre_exec((*C.pcre)(unsafe.Pointer(pcre)), extra, subjectPtr(subject), C.int(len(subject)), C.int(startOffset), C.int(options), oVectorPtr, C.int(len(oVector)), stack.ptr())
+	}
+
+	copyOVector(oVector, *oVectorC)
+	return Error(r)
+}
+
+// fullinfo stores the information about pcre asked for by what in where.
+func (pcre *PCRE) fullinfo(extra *PCREExtra, what Info, where unsafe.Pointer) error {
+	if what == infoUnsupported {
+		return ErrBadOption
+	}
+	if rc := C.pcre_fullinfo((*C.pcre)(unsafe.Pointer(pcre)), (*C.pcre_extra)(unsafe.Pointer(extra)), C.int(what), where); rc != 0 {
+		return Error(rc)
+	}
+	return nil
+}
+
+/*
 // Specific error codes for UTF-16 validity checks
 
 #define PCRE_UTF16_ERR0              0
@@ -184,27 +434,6 @@ PCRE_ERROR_DFA_BADRESTART
 
 // Request types for pcre_fullinfo()
 
-// Request types for pcre_config(). Do not re-arrange, in order to remain compatible.
-
-#define PCRE_CONFIG_UTF8                    0
-#define PCRE_CONFIG_NEWLINE                 1
-#define PCRE_CONFIG_LINK_SIZE               2
-#define PCRE_CONFIG_POSIX_MALLOC_THRESHOLD  3
-#define PCRE_CONFIG_MATCH_LIMIT             4
-#define PCRE_CONFIG_STACKRECURSE            5
-#define PCRE_CONFIG_UNICODE_PROPERTIES      6
-#define PCRE_CONFIG_MATCH_LIMIT_RECURSION   7
-#define PCRE_CONFIG_BSR                     8
-#define PCRE_CONFIG_JIT                     9
-#define PCRE_CONFIG_UTF16                  10
-#define PCRE_CONFIG_JITTARGET              11
-
-// Request types for pcre_study(). Do not re-arrange, in order to remain compatible.
-
-#define PCRE_STUDY_JIT_COMPILE                0x0001
-#define PCRE_STUDY_JIT_PARTIAL_SOFT_COMPILE   0x0002
-#define PCRE_STUDY_JIT_PARTIAL_HARD_COMPILE   0x0004
-
 // Bit flags for the pcre[16]_extra structure. Do not re-arrange or redefine these bits, just add new ones on the end, in order to remain compatible.
 
 #define PCRE_EXTRA_STUDY_DATA             0x0001
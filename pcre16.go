@@ -0,0 +1,113 @@
+//go:build pcre16 && !pcre2
+// +build pcre16,!pcre2
+
+package pcre
+
+// #cgo LDFLAGS: -lpcre16
+// #include <pcre.h>
+//
+// static void call_pcre16_free(void *ptr) {
+//     pcre16_free(ptr);
+// }
+import "C"
+
+import (
+	"unicode/utf16"
+	"unsafe"
+)
+
+func init() {
+	utf16Config = func() bool {
+		var value C.int
+		return C.pcre16_config(C.PCRE_CONFIG_UTF16, unsafe.Pointer(&value)) == 0 && value == 1
+	}
+}
+
+// PCRE16 is a pattern compiled by the 16-bit library, libpcre16, which
+// matches subjects made of 16-bit code units such as UTF-16 text. It is only
+// available when building with the pcre16 tag.
+type PCRE16 C.struct_real_pcre16
+
+// PCRE16Extra holds the study data of a PCRE16.
+type PCRE16Extra C.struct_pcre16_extra
+
+// Compile16 compiles expr with options for the 16-bit library. Pass UTF16
+// to treat subjects as UTF-16 rather than as single code units. The Offset
+// of a returned CompileError is a byte offset in expr, like for Compile.
+func Compile16(expr string, options CompileOption) (*PCRE16, error) {
+	var (
+		errCode   C.int
+		errPtr    *C.char
+		errOffset C.int
+	)
+
+	pattern := append(utf16.Encode([]rune(expr)), 0)
+
+	re := C.pcre16_compile2((*C.ushort)(unsafe.Pointer(&pattern[0])), C.int(options), &errCode, &errPtr, &errOffset, nil)
+	if re == nil {
+		return nil, wideCompileError(expr, errCode, errPtr, errOffset, utf16Units)
+	}
+	track(unsafe.Pointer(re))
+	return (*PCRE16)(unsafe.Pointer(re)), nil
+}
+
+// utf16Units returns the number of 16-bit code units r is encoded in.
+func utf16Units(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// Study16 is Study for patterns compiled by Compile16.
+func Study16(code *PCRE16, options StudyOption) (*PCRE16Extra, error) {
+	var errPtr *C.char
+
+	extra := C.pcre16_study(code.ptr(), C.int(options), &errPtr)
+	if err := wideStudied(unsafe.Pointer(extra), errPtr); err != nil {
+		return nil, err
+	}
+	return (*PCRE16Extra)(unsafe.Pointer(extra)), nil
+}
+
+// Free releases the study data. Freeing an extra again does nothing.
+func (pcreExtra *PCRE16Extra) Free() {
+	if !untrack(unsafe.Pointer(pcreExtra)) {
+		return
+	}
+	C.pcre16_free_study((*C.pcre16_extra)(unsafe.Pointer(pcreExtra)))
+}
+
+// Free releases the compiled pattern. Freeing a pattern again does nothing.
+func (pcre *PCRE16) Free() {
+	if !untrack(unsafe.Pointer(pcre)) {
+		return
+	}
+	C.call_pcre16_free(unsafe.Pointer(pcre))
+}
+
+func (pcre *PCRE16) ptr() *C.pcre16 { return (*C.pcre16)(unsafe.Pointer(pcre)) }
+
+// Exec matches subject against the pattern starting at startOffset. The
+// subject is read in place, and startOffset and the offsets stored in
+// oVector count 16-bit code units. Invalid UTF-16 is reported as
+// ErrBadUTF16, ErrBadUTF16Offset or ErrShortUTF16.
+func (pcre *PCRE16) Exec(extra *PCRE16Extra, subject []uint16, startOffset int, options ExecOption, oVector []int) Error {
+	// pcre16_exec rejects a nil subject even when it is empty.
+	var empty C.ushort
+	subjectPtr := &empty
+	if len(subject) > 0 {
+		subjectPtr = (*C.ushort)(unsafe.Pointer(&subject[0]))
+	}
+
+	return wideExec(oVector, utf16Errors, func(oVector *C.int, size C.int) C.int {
+		return C.pcre16_exec(pcre.ptr(), (*C.pcre16_extra)(unsafe.Pointer(extra)), subjectPtr, C.int(len(subject)), C.int(startOffset), C.int(options), oVector, size)
+	})
+}
+
+// CaptureCount returns the number of capturing subpatterns.
+func (pcre *PCRE16) CaptureCount() int {
+	var count C.int
+	rc := C.pcre16_fullinfo(pcre.ptr(), nil, C.PCRE_INFO_CAPTURECOUNT, unsafe.Pointer(&count))
+	return wideCaptureCount("pcre16_fullinfo", rc, count)
+}
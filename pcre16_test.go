@@ -0,0 +1,69 @@
+//go:build pcre16 && !pcre2
+// +build pcre16,!pcre2
+
+package pcre
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestCompile16(t *testing.T) {
+	// 𝄞 takes two code units and four bytes.
+	_, err := Compile16("𝄞(", UTF16)
+	var compileErr *CompileError
+	if !errors.As(err, &compileErr) {
+		t.Fatalf("Compile16 of an unclosed group: %v", err)
+	}
+	if compileErr.Offset != len("𝄞(") {
+		t.Errorf("Offset = %d, want %d", compileErr.Offset, len("𝄞("))
+	}
+	if caret := compileErr.Caret(); caret != "𝄞(\n  ^" {
+		t.Errorf("Caret = %q", caret)
+	}
+}
+
+func TestExec16(t *testing.T) {
+	re, err := Compile16("é+", UTF16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer re.Free()
+
+	oVector := make([]int, 3)
+	if e := re.Exec(nil, utf16.Encode([]rune("𝄞éé")), 0, 0, oVector); e < 0 {
+		t.Fatalf("Exec = %v", e)
+	}
+	if !reflect.DeepEqual(oVector[:2], []int{2, 4}) {
+		t.Errorf("match at %v, want [2 4]", oVector[:2])
+	}
+
+	e := re.Exec(nil, []uint16{'x', 0xdc00}, 0, 0, oVector)
+	if e != ErrBadUTF16 || e.Error() != "pcre: invalid UTF-16 in subject" {
+		t.Errorf("Exec of a lone low surrogate = %v, want ErrBadUTF16", e)
+	}
+	if e := re.Exec(nil, []uint16{'x', 0xd800}, 0, 0, oVector); e != ErrShortUTF16 && e != ErrBadUTF16 {
+		t.Errorf("Exec of a truncated surrogate pair = %v, want ErrShortUTF16 or ErrBadUTF16", e)
+	}
+
+	if !Config().UTF16 {
+		t.Error("Config().UTF16 = false with libpcre16 linked")
+	}
+}
+
+func TestFree16(t *testing.T) {
+	re, err := Compile16("a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra, err := Study16(re, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra.Free()
+	extra.Free()
+	re.Free()
+	re.Free()
+}
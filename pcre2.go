@@ -0,0 +1,501 @@
+//go:build pcre2
+// +build pcre2
+
+package pcre
+
+// #cgo LDFLAGS: -lpcre2-8
+// #define PCRE2_CODE_UNIT_WIDTH 8
+// #include <pcre2.h>
+//
+// static pcre2_code *go_pcre2_compile(const unsigned char *pattern, size_t length, uint32_t options, uint32_t newline, uint32_t bsr, const unsigned char *tables, int *errCode, size_t *errOffset) {
+//     pcre2_compile_context *ccontext = NULL;
+//     pcre2_code *code;
+//
+//     if (newline != 0 || bsr != 0 || tables != NULL) {
+//         ccontext = pcre2_compile_context_create(NULL);
+//         if (ccontext == NULL) {
+//             *errCode = PCRE2_ERROR_NOMEMORY;
+//             *errOffset = 0;
+//             return NULL;
+//         }
+//         if (newline != 0) {
+//             pcre2_set_newline(ccontext, newline);
+//         }
+//         if (bsr != 0) {
+//             pcre2_set_bsr(ccontext, bsr);
+//         }
+//         if (tables != NULL) {
+//             pcre2_set_character_tables(ccontext, tables);
+//         }
+//     }
+//     code = pcre2_compile(pattern, length, options, errCode, errOffset, ccontext);
+//     pcre2_compile_context_free(ccontext);
+//     return code;
+// }
+//
+// // The match context and match data are kept per thread and reused by
+// // every call made on it, which spares an allocation per match.
+// static __thread pcre2_match_context *thread_mcontext;
+// static __thread pcre2_match_data *thread_data;
+// static __thread uint32_t thread_data_pairs;
+//
+// // Sets *mcontext to the match context of this thread, with the given limits
+// // and JIT stack, a zero limit is the library default. It returns 0 when out
+// // of memory.
+// int go_pcre2_match_context(uint32_t matchLimit, uint32_t depthLimit, pcre2_jit_stack *stack, pcre2_match_context **mcontext) {
+//     if (thread_mcontext == NULL) {
+//         thread_mcontext = pcre2_match_context_create(NULL);
+//         if (thread_mcontext == NULL) {
+//             return 0;
+//         }
+//     }
+//     if (matchLimit == 0) {
+//         pcre2_config(PCRE2_CONFIG_MATCHLIMIT, &matchLimit);
+//     }
+//     if (depthLimit == 0) {
+//         pcre2_config(PCRE2_CONFIG_DEPTHLIMIT, &depthLimit);
+//     }
+//     pcre2_set_match_limit(thread_mcontext, matchLimit);
+//     pcre2_set_depth_limit(thread_mcontext, depthLimit);
+//     pcre2_jit_stack_assign(thread_mcontext, NULL, stack);
+//     *mcontext = thread_mcontext;
+//     return 1;
+// }
+//
+// // Returns match data of this thread with room for at least pairs offset
+// // pairs, or NULL when out of memory.
+// static pcre2_match_data *match_data(uint32_t pairs) {
+//     if (thread_data == NULL || thread_data_pairs < pairs) {
+//         pcre2_match_data_free(thread_data);
+//         thread_data_pairs = 0;
+//         thread_data = pcre2_match_data_create(pairs, NULL);
+//         if (thread_data == NULL) {
+//             return NULL;
+//         }
+//         thread_data_pairs = pairs;
+//     }
+//     return thread_data;
+// }
+//
+// // Runs pcre2_match, or pcre2_jit_match if jit is set, and stores the
+// // result the way pcre_exec does, in an int vector whose first two thirds
+// // hold the offset pairs. Invalid UTF-8 is reported as
+// // PCRE2_ERROR_UTF8_ERR1 with the offset and reason in the first pair.
+// int go_pcre2_match(const pcre2_code *code, int jit, const unsigned char *subject, size_t length, size_t startOffset, uint32_t options, int *oVector, int oVectorSize, uint32_t matchLimit, uint32_t depthLimit, pcre2_jit_stack *stack, const unsigned char **mark) {
+//     pcre2_match_context *mcontext;
+//     pcre2_match_data *data;
+//     PCRE2_SIZE *ov;
+//     uint32_t pairs = oVectorSize / 3, i;
+//     int rc;
+//
+//     if (pairs == 0) {
+//         pairs = 1;
+//     }
+//     if (!go_pcre2_match_context(matchLimit, depthLimit, stack, &mcontext)) {
+//         return PCRE2_ERROR_NOMEMORY;
+//     }
+//     data = match_data(pairs);
+//     if (data == NULL) {
+//         return PCRE2_ERROR_NOMEMORY;
+//     }
+//
+//     if (jit) {
+//         rc = pcre2_jit_match(code, subject, length, startOffset, options, data, mcontext);
+//     } else {
+//         rc = pcre2_match(code, subject, length, startOffset, options, data, mcontext);
+//     }
+//     ov = pcre2_get_ovector_pointer(data);
+//     if (rc >= 0 || rc == PCRE2_ERROR_PARTIAL) {
+//         // The reused match data may have room for more pairs than asked
+//         // for, report a vector too small as pcre_exec would.
+//         if (rc > (int)pairs) {
+//             rc = 0;
+//         }
+//         for (i = 0; i < pairs && 2*i+1 < (uint32_t)oVectorSize; i++) {
+//             oVector[2*i] = ov[2*i] == PCRE2_UNSET ? -1 : (int)ov[2*i];
+//             oVector[2*i+1] = ov[2*i+1] == PCRE2_UNSET ? -1 : (int)ov[2*i+1];
+//         }
+//     } else if (rc <= PCRE2_ERROR_UTF8_ERR1 && rc >= PCRE2_ERROR_UTF8_ERR21) {
+//         if (oVectorSize >= 2) {
+//             oVector[0] = (int)pcre2_get_startchar(data);
+//             oVector[1] = PCRE2_ERROR_UTF8_ERR1 - rc + 1;
+//         }
+//         rc = PCRE2_ERROR_UTF8_ERR1;
+//     }
+//     if (mark != NULL) {
+//         *mark = pcre2_get_mark(data);
+//     }
+//     return rc;
+// }
+import "C"
+
+import "unsafe"
+
+const (
+	Major = C.PCRE2_MAJOR
+	Minor = C.PCRE2_MINOR
+	Date  = C.PCRE2_DATE
+)
+
+const (
+	StudyJITCompile            StudyOption = C.PCRE2_JIT_COMPLETE
+	StudyJITPartialSoftCompile StudyOption = C.PCRE2_JIT_PARTIAL_SOFT // JIT for matching with PartialSoft
+	StudyJITPartialHardCompile StudyOption = C.PCRE2_JIT_PARTIAL_HARD // JIT for matching with PartialHard
+)
+
+// jitExecOptions are the Exec options the JIT can handle, pcre2_match falls
+// back to the interpreter when any other option is given.
+const jitExecOptions = NoUTF8Check | NotBOL | NotEOL | NotEmpty | NotEmptyAtStart | PartialSoft | PartialHard
+
+// UsesJIT reports whether Exec, called with pcreExtra and options, runs on
+// JIT compiled code instead of the interpreter. Partial matching additionally
+// requires that the pattern was studied for the same partial mode.
+func (pcreExtra *PCREExtra) UsesJIT(options ExecOption) bool {
+	if pcreExtra == nil || options&^jitExecOptions != 0 {
+		return false
+	}
+	return pcreExtra.jit&jitMode(options) != 0
+}
+
+// PCRE2 sets the newline and \R conventions in a compile context rather than
+// with option bits, the binding keeps them above the 32 bits the library
+// uses.
+const (
+	newlineShift = 32
+	newlineMask  = 0xf << newlineShift
+	bsrShift     = 36
+	bsrMask      = 0xf << bsrShift
+)
+
+// Options for Compile.
+const (
+	Caseless         CompileOption = C.PCRE2_CASELESS
+	Multiline        CompileOption = C.PCRE2_MULTILINE
+	DotAll           CompileOption = C.PCRE2_DOTALL
+	Extended         CompileOption = C.PCRE2_EXTENDED
+	DollarEndOnly    CompileOption = C.PCRE2_DOLLAR_ENDONLY
+	UnGreedy         CompileOption = C.PCRE2_UNGREEDY
+	UTF8             CompileOption = C.PCRE2_UTF
+	UTF16            CompileOption = C.PCRE2_UTF // Same bit as UTF8, like with libpcre
+	UTF32            CompileOption = C.PCRE2_UTF // Same bit as UTF8, like with libpcre
+	Extra            CompileOption = 0           // libpcre2 always rejects unknown escapes, as Extra makes libpcre do
+	NoAutoCapture    CompileOption = C.PCRE2_NO_AUTO_CAPTURE
+	AutoCallout      CompileOption = C.PCRE2_AUTO_CALLOUT
+	FirstLine        CompileOption = C.PCRE2_FIRSTLINE
+	DupNames         CompileOption = C.PCRE2_DUPNAMES
+	NewlineCR        CompileOption = C.PCRE2_NEWLINE_CR << newlineShift
+	NewlineLF        CompileOption = C.PCRE2_NEWLINE_LF << newlineShift
+	NewlineCRLF      CompileOption = C.PCRE2_NEWLINE_CRLF << newlineShift
+	NewlineAny       CompileOption = C.PCRE2_NEWLINE_ANY << newlineShift
+	NewlineAnyCRLF   CompileOption = C.PCRE2_NEWLINE_ANYCRLF << newlineShift
+	NewlineNUL       CompileOption = C.PCRE2_NEWLINE_NUL << newlineShift
+	BSRAnyCRLF       CompileOption = C.PCRE2_BSR_ANYCRLF << bsrShift
+	BSRUnicode       CompileOption = C.PCRE2_BSR_UNICODE << bsrShift
+	JavascriptCompat CompileOption = C.PCRE2_ALT_BSUX | C.PCRE2_ALLOW_EMPTY_CLASS | C.PCRE2_MATCH_UNSET_BACKREF
+	NoStartOptimize  CompileOption = C.PCRE2_NO_START_OPTIMIZE
+	NoStartOptimise  CompileOption = C.PCRE2_NO_START_OPTIMIZE // <3 Rule britannia
+	UCP              CompileOption = C.PCRE2_UCP
+)
+
+// Options for Exec and the other matching functions.
+const (
+	NotBOL          ExecOption = C.PCRE2_NOTBOL
+	NotEOL          ExecOption = C.PCRE2_NOTEOL
+	NotEmpty        ExecOption = C.PCRE2_NOTEMPTY
+	PartialSoft     ExecOption = C.PCRE2_PARTIAL_SOFT
+	Partial         ExecOption = C.PCRE2_PARTIAL_SOFT
+	PartialHard     ExecOption = C.PCRE2_PARTIAL_HARD
+	NotEmptyAtStart ExecOption = C.PCRE2_NOTEMPTY_ATSTART
+	NoJIT           ExecOption = C.PCRE2_NO_JIT // Match with the interpreter even if the pattern was JIT compiled
+)
+
+// Options libpcre2 accepts both when compiling and when matching, where they
+// override the ones the pattern was compiled with.
+const (
+	Anchored     = C.PCRE2_ANCHORED
+	NoUTF8Check  = C.PCRE2_NO_UTF_CHECK
+	NoUTF16Check = C.PCRE2_NO_UTF_CHECK
+	NoUTF32Check = C.PCRE2_NO_UTF_CHECK
+)
+
+var compileOptionNames = []optionName{
+	{"JavascriptCompat", C.PCRE2_ALT_BSUX | C.PCRE2_ALLOW_EMPTY_CLASS | C.PCRE2_MATCH_UNSET_BACKREF, 0},
+	{"Caseless", C.PCRE2_CASELESS, 0},
+	{"Multiline", C.PCRE2_MULTILINE, 0},
+	{"DotAll", C.PCRE2_DOTALL, 0},
+	{"Extended", C.PCRE2_EXTENDED, 0},
+	{"Anchored", C.PCRE2_ANCHORED, 0},
+	{"DollarEndOnly", C.PCRE2_DOLLAR_ENDONLY, 0},
+	{"UnGreedy", C.PCRE2_UNGREEDY, 0},
+	{"UTF8", C.PCRE2_UTF, 0},
+	{"NoAutoCapture", C.PCRE2_NO_AUTO_CAPTURE, 0},
+	{"NoUTF8Check", C.PCRE2_NO_UTF_CHECK, 0},
+	{"AutoCallout", C.PCRE2_AUTO_CALLOUT, 0},
+	{"FirstLine", C.PCRE2_FIRSTLINE, 0},
+	{"DupNames", C.PCRE2_DUPNAMES, 0},
+	{"NoStartOptimize", C.PCRE2_NO_START_OPTIMIZE, 0},
+	{"UCP", C.PCRE2_UCP, 0},
+	{"NewlineCR", C.PCRE2_NEWLINE_CR << newlineShift, newlineMask},
+	{"NewlineLF", C.PCRE2_NEWLINE_LF << newlineShift, newlineMask},
+	{"NewlineCRLF", C.PCRE2_NEWLINE_CRLF << newlineShift, newlineMask},
+	{"NewlineAny", C.PCRE2_NEWLINE_ANY << newlineShift, newlineMask},
+	{"NewlineAnyCRLF", C.PCRE2_NEWLINE_ANYCRLF << newlineShift, newlineMask},
+	{"NewlineNUL", C.PCRE2_NEWLINE_NUL << newlineShift, newlineMask},
+	{"BSRAnyCRLF", C.PCRE2_BSR_ANYCRLF << bsrShift, bsrMask},
+	{"BSRUnicode", C.PCRE2_BSR_UNICODE << bsrShift, bsrMask},
+}
+
+var execOptionNames = []optionName{
+	{"Anchored", C.PCRE2_ANCHORED, 0},
+	{"NotBOL", C.PCRE2_NOTBOL, 0},
+	{"NotEOL", C.PCRE2_NOTEOL, 0},
+	{"NotEmpty", C.PCRE2_NOTEMPTY, 0},
+	{"NoUTF8Check", C.PCRE2_NO_UTF_CHECK, 0},
+	{"PartialSoft", C.PCRE2_PARTIAL_SOFT, 0},
+	{"PartialHard", C.PCRE2_PARTIAL_HARD, 0},
+	{"NotEmptyAtStart", C.PCRE2_NOTEMPTY_ATSTART, 0},
+	{"NoJIT", C.PCRE2_NO_JIT, 0},
+	{"SubstituteGlobal", C.PCRE2_SUBSTITUTE_GLOBAL, 0},
+	{"SubstituteExtended", C.PCRE2_SUBSTITUTE_EXTENDED, 0},
+	{"SubstituteUnsetEmpty", C.PCRE2_SUBSTITUTE_UNSET_EMPTY, 0},
+	{"SubstituteUnknownUnset", C.PCRE2_SUBSTITUTE_UNKNOWN_UNSET, 0},
+}
+
+var studyOptionNames = []optionName{
+	{"StudyJITCompile", C.PCRE2_JIT_COMPLETE, 0},
+	{"StudyJITPartialSoftCompile", C.PCRE2_JIT_PARTIAL_SOFT, 0},
+	{"StudyJITPartialHardCompile", C.PCRE2_JIT_PARTIAL_HARD, 0},
+}
+
+type PCRE C.pcre2_code_8
+
+func (pcre *PCRE) ptr() *C.pcre2_code_8 { return (*C.pcre2_code_8)(unsafe.Pointer(pcre)) }
+
+// Compile compiles expr with options. A nil tables uses the character tables
+// built into libpcre2.
+func Compile(expr string, options CompileOption, tables *Tables) (*PCRE, error) {
+	var (
+		errCode   C.int
+		errOffset C.size_t
+	)
+
+	pattern := C.CString(expr)
+	defer C.free(unsafe.Pointer(pattern))
+
+	var tablesPtr *C.uchar
+	if tables != nil {
+		tablesPtr = tables.ptr
+	}
+
+	re := C.go_pcre2_compile((*C.uchar)(unsafe.Pointer(pattern)), C.size_t(len(expr)),
+		C.uint32_t(options&^(newlineMask|bsrMask)), C.uint32_t(options&newlineMask>>newlineShift), C.uint32_t(options&bsrMask>>bsrShift),
+		tablesPtr, &errCode, &errOffset)
+	if re == nil {
+		return nil, &CompileError{
+			Message: errorMessage(errCode),
+			Code:    int(errCode),
+			Offset:  int(errOffset),
+			Pattern: expr,
+		}
+	}
+
+	track(unsafe.Pointer(re))
+	if tables != nil {
+		holdTables((*PCRE)(unsafe.Pointer(re)), tables)
+	}
+	return (*PCRE)(unsafe.Pointer(re)), nil
+}
+
+// Free releases the compiled pattern. Freeing a pattern again does nothing.
+func (pcre *PCRE) Free() {
+	if !untrack(unsafe.Pointer(pcre)) {
+		return
+	}
+	releaseTables(pcre)
+	C.pcre2_code_free_8(pcre.ptr())
+}
+
+// PCREExtra holds the per pattern settings that libpcre keeps in its
+// pcre_extra. PCRE2 optimizes patterns while compiling them and JIT compiles
+// them in place, so unlike with libpcre it holds no memory of its own.
+type PCREExtra struct {
+	jit    StudyOption // The StudyJIT options the pattern was JIT compiled for
+	limits Limits
+	stack  *JITStack
+}
+
+// Study JIT compiles code when options contain one of the StudyJIT options,
+// otherwise there is nothing left to do after pcre2_compile. A libpcre2
+// built without JIT support leaves the pattern to the interpreter.
+func Study(code *PCRE, options StudyOption, _ interface{}) (*PCREExtra, error) {
+	extra := &PCREExtra{}
+	if options&(StudyJITCompile|StudyJITPartialSoftCompile|StudyJITPartialHardCompile) == 0 {
+		return extra, nil
+	}
+
+	switch rc := C.pcre2_jit_compile_8(code.ptr(), C.uint32_t(options)); rc {
+	case 0:
+		extra.jit = options & (StudyJITCompile | StudyJITPartialSoftCompile | StudyJITPartialHardCompile)
+	case C.PCRE2_ERROR_JIT_BADOPTION:
+	default:
+		return nil, Error(rc)
+	}
+	return extra, nil
+}
+
+// Free is a no-op, it exists for symmetry with the libpcre backend.
+func (pcreExtra *PCREExtra) Free() {}
+
+// NewExtra returns an empty PCREExtra, for setting limits on a pattern that
+// was not studied.
+func NewExtra() *PCREExtra { return &PCREExtra{} }
+
+// Limits bound the work pcre2_match may do before giving up, which protects
+// against catastrophic backtracking. A zero field leaves the limit at the
+// library default. Exceeding a limit makes Exec return ErrMatchLimit or
+// ErrRecursionLimit.
+type Limits struct {
+	Match     uint // Maximum number of internal match() calls
+	Recursion uint // Maximum backtracking depth, the depth limit of PCRE2
+}
+
+// SetLimits makes every Exec with pcreExtra use limits, zero fields clear
+// a previously set limit.
+func (pcreExtra *PCREExtra) SetLimits(limits Limits) { pcreExtra.limits = limits }
+
+// Limits returns the limits set on pcreExtra.
+func (pcreExtra *PCREExtra) Limits() Limits { return pcreExtra.limits }
+
+// AssignJITStack makes every Exec with pcreExtra run on stack, unless the
+// call brings its own through a MatchContext. A stack must not be used by
+// two matches at once. A nil stack restores the default 32K stack.
+func (pcreExtra *PCREExtra) AssignJITStack(stack *JITStack) { pcreExtra.stack = stack }
+
+// Exec matches subject against the compiled pattern starting at startOffset.
+// If extra is non-nil the settings it holds are used. A pattern that was JIT
+// compiled runs on the JIT whenever the options allow it.
+func (pcre *PCRE) Exec(extra *PCREExtra, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.ExecContext(extra, nil, subject, startOffset, options, oVector)
+}
+
+// ExecBytes is like Exec, but matches the bytes of subject. The subject is
+// read in place rather than copied.
+func (pcre *PCRE) ExecBytes(extra *PCREExtra, subject []byte, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.Exec(extra, bytesToString(subject), startOffset, options, oVector)
+}
+
+// ExecLimits is like Exec, but applies limits to this call only. Non-zero
+// limits take precedence over those stored in extra.
+func (pcre *PCRE) ExecLimits(extra *PCREExtra, limits Limits, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.ExecContext(extra, &MatchContext{Limits: limits}, subject, startOffset, options, oVector)
+}
+
+// MatchContext holds settings for a single ExecContext call, which lets
+// concurrent calls sharing one PCREExtra each use their own.
+type MatchContext struct {
+	// Limits override the ones stored in the PCREExtra, see ExecLimits.
+	Limits Limits
+
+	// JITStack, if non-nil, is the stack a JIT compiled pattern runs on for
+	// this call. It must not be in use by another call at the same time.
+	JITStack *JITStack
+
+	// Mark, if non-nil, receives the name of the last (*MARK), (*PRUNE) or
+	// (*THEN) passed on the matching path, or "" if there was none. When
+	// the match fails it receives the last name encountered.
+	Mark *string
+}
+
+// ExecContext is like Exec, but applies the settings in ctx to this call.
+// A nil ctx behaves like Exec.
+func (pcre *PCRE) ExecContext(extra *PCREExtra, ctx *MatchContext, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	var mark *string
+	if ctx != nil {
+		mark = ctx.Mark
+	}
+	limits, stack := extra.settings(ctx)
+	return pcre.match(false, limits, stack, subject, startOffset, options, oVector, mark)
+}
+
+// settings returns the limits and JIT stack for a call with ctx, those in
+// ctx taking precedence over the ones of pcreExtra. Both may be nil.
+func (pcreExtra *PCREExtra) settings(ctx *MatchContext) (limits Limits, stack *JITStack) {
+	if pcreExtra != nil {
+		limits, stack = pcreExtra.limits, pcreExtra.stack
+	}
+	if ctx != nil {
+		if ctx.Limits.Match > 0 {
+			limits.Match = ctx.Limits.Match
+		}
+		if ctx.Limits.Recursion > 0 {
+			limits.Recursion = ctx.Limits.Recursion
+		}
+		if ctx.JITStack != nil {
+			stack = ctx.JITStack
+		}
+	}
+	return limits, stack
+}
+
+// ExecJIT is like Exec, but runs the JIT compiled code of the pattern
+// directly with pcre2_jit_match, which skips the checks pcre2_match makes on
+// every call. It fails with ErrJITBadOption unless extra.UsesJIT(options),
+// and with ErrBadOffset if startOffset is not within subject.
+// The subject is not checked for valid UTF-8, matching an invalid one is
+// undefined. stack is the JIT stack to run on, nil uses 32K of machine
+// stack; a stack assigned with AssignJITStack is not used. The limits
+// stored in extra apply.
+func (pcre *PCRE) ExecJIT(extra *PCREExtra, stack *JITStack, subject string, startOffset int, options ExecOption, oVector []int) Error {
+	if !extra.UsesJIT(options) {
+		return ErrJITBadOption
+	}
+	// The JIT does not check the offset, one outside subject would have it
+	// read out of bounds.
+	if startOffset < 0 || startOffset > len(subject) {
+		return ErrBadOffset
+	}
+	return pcre.match(true, extra.limits, stack, subject, startOffset, options, oVector, nil)
+}
+
+// match runs pcre2_match, or pcre2_jit_match if jit is set, and stores the
+// last mark passed in mark if it is non-nil.
+func (pcre *PCRE) match(jit bool, limits Limits, stack *JITStack, subject string, startOffset int, options ExecOption, oVector []int, mark *string) Error {
+	oVectorC := getOVector(len(oVector))
+	defer putOVector(oVectorC)
+
+	var oVectorPtr *C.int
+	if len(oVector) > 0 {
+		oVectorPtr = &(*oVectorC)[0]
+	}
+
+	var jitFlag C.int
+	if jit {
+		jitFlag = 1
+	}
+
+	subjectC := (*C.uchar)(unsafe.Pointer(subjectPtr(subject)))
+	var r C.int
+	if mark == nil {
+		r = C.go_pcre2_match(pcre.ptr(), jitFlag, subjectC, C.size_t(len(subject)), C.size_t(startOffset),
+			C.uint32_t(options), oVectorPtr, C.int(len(oVector)), C.uint32_t(limits.Match), C.uint32_t(limits.Recursion), stack.ptr(), nil)
+	} else {
+		// markC is only declared on this path, as taking its address for C
+		// moves it to the heap.
+		var markC *C.uchar
+		r = C.go_pcre2_match(pcre.ptr(), jitFlag, subjectC, C.size_t(len(subject)), C.size_t(startOffset),
+			C.uint32_t(options), oVectorPtr, C.int(len(oVector)), C.uint32_t(limits.Match), C.uint32_t(limits.Recursion), stack.ptr(), &markC)
+		*mark = ""
+		if markC != nil {
+			*mark = C.GoString((*C.char)(unsafe.Pointer(markC)))
+		}
+	}
+
+	copyOVector(oVector, *oVectorC)
+	return Error(r)
+}
+
+// ExecContextBytes is like ExecContext, but matches the bytes of subject,
+// which are read in place.
+func (pcre *PCRE) ExecContextBytes(extra *PCREExtra, ctx *MatchContext, subject []byte, startOffset int, options ExecOption, oVector []int) Error {
+	return pcre.ExecContext(extra, ctx, bytesToString(subject), startOffset, options, oVector)
+}
@@ -0,0 +1,48 @@
+//go:build pcre2
+// +build pcre2
+
+package pcre
+
+// #define PCRE2_CODE_UNIT_WIDTH 8
+// #include <pcre2.h>
+//
+// int go_pcre2_match(const pcre2_code *code, int jit, const unsigned char *subject, size_t length, size_t startOffset, uint32_t options, int *oVector, int oVectorSize, uint32_t matchLimit, uint32_t depthLimit, pcre2_jit_stack *stack, const unsigned char **mark);
+//
+// static void go_pcre2_match_batch(const pcre2_code *code, const unsigned char *subjects, int count, const int *lengths, uint32_t options, int *results, int *locs, uint32_t matchLimit, uint32_t depthLimit, pcre2_jit_stack *stack) {
+//     int oVector[3], i;
+//
+//     for (i = 0; i < count; i++) {
+//         oVector[0] = oVector[1] = -1;
+//         results[i] = go_pcre2_match(code, 0, subjects, lengths[i], 0, options, oVector, 3, matchLimit, depthLimit, stack, NULL);
+//         if (results[i] == PCRE2_ERROR_NOMATCH) {
+//             oVector[0] = oVector[1] = -1;
+//         }
+//         locs[2*i] = oVector[0];
+//         locs[2*i+1] = oVector[1];
+//         subjects += lengths[i];
+//     }
+// }
+import "C"
+
+import "unsafe"
+
+// ExecBatch matches each of subjects against the compiled pattern from its
+// start, all in a single call into libpcre2. The result of subjects[i] is
+// stored in results[i], as Exec would return it. When locs is non-nil the
+// offsets of the match are stored in locs[2*i] and locs[2*i+1], -1 if there
+// was none; for an error they are what Error.Detail expects. results must be
+// at least as long as subjects, and locs twice as long.
+//
+// Only the Limits and JITStack of ctx are used, a nil ctx behaves like
+// Exec. The subjects are copied into one buffer for the call.
+func (pcre *PCRE) ExecBatch(extra *PCREExtra, ctx *MatchContext, subjects []string, options ExecOption, results []Error, locs []int) {
+	if len(subjects) == 0 {
+		return
+	}
+
+	limits, stack := extra.settings(ctx)
+	b := newBatch(subjects)
+	C.go_pcre2_match_batch(pcre.ptr(), (*C.uchar)(unsafe.Pointer(b.subjectsPtr())), C.int(b.count), b.lengths(), C.uint32_t(options),
+		b.results(), b.locs(), C.uint32_t(limits.Match), C.uint32_t(limits.Recursion), stack.ptr())
+	b.done(results, locs)
+}
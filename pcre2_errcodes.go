@@ -0,0 +1,124 @@
+//go:build pcre2
+// +build pcre2
+
+package pcre
+
+// #define PCRE2_CODE_UNIT_WIDTH 8
+// #include <pcre2.h>
+import "C"
+
+import "unsafe"
+
+// PCRE2 reports each kind of invalid UTF-8 with its own code, Exec folds
+// them into ErrBadUTF8 and passes the reason on in the output vector like
+// libpcre does.
+const (
+	ErrNoMatch           Error = C.PCRE2_ERROR_NOMATCH
+	ErrNull              Error = C.PCRE2_ERROR_NULL
+	ErrBadOption         Error = C.PCRE2_ERROR_BADOPTION
+	ErrBadMagic          Error = C.PCRE2_ERROR_BADMAGIC
+	ErrNoMemory          Error = C.PCRE2_ERROR_NOMEMORY
+	ErrNoSubstring       Error = C.PCRE2_ERROR_NOSUBSTRING
+	ErrNoUniqueSubstring Error = C.PCRE2_ERROR_NOUNIQUESUBSTRING
+	ErrMatchLimit        Error = C.PCRE2_ERROR_MATCHLIMIT
+	ErrCallout           Error = C.PCRE2_ERROR_CALLOUT
+	ErrBadUTF8           Error = C.PCRE2_ERROR_UTF8_ERR1
+	ErrShortUTF8         Error = ErrBadUTF8 // PCRE2 reports truncated characters as invalid UTF-8
+	ErrBadUTF8Offset     Error = C.PCRE2_ERROR_BADUTFOFFSET
+	ErrPartial           Error = C.PCRE2_ERROR_PARTIAL
+	ErrInternal          Error = C.PCRE2_ERROR_INTERNAL
+	ErrDFAUItem          Error = C.PCRE2_ERROR_DFA_UITEM
+	ErrDFAUCond          Error = C.PCRE2_ERROR_DFA_UCOND
+	ErrDFAWSSize         Error = C.PCRE2_ERROR_DFA_WSSIZE
+	ErrDFARecurse        Error = C.PCRE2_ERROR_DFA_RECURSE
+	ErrRecursionLimit    Error = C.PCRE2_ERROR_DEPTHLIMIT
+	ErrBadOffset         Error = C.PCRE2_ERROR_BADOFFSET
+	ErrRecurseLoop       Error = C.PCRE2_ERROR_RECURSELOOP
+	ErrJITStackLimit     Error = C.PCRE2_ERROR_JIT_STACKLIMIT
+	ErrBadMode           Error = C.PCRE2_ERROR_BADMODE
+	ErrDFABadRestart     Error = C.PCRE2_ERROR_DFA_BADRESTART
+	ErrJITBadOption      Error = C.PCRE2_ERROR_JIT_BADOPTION
+	ErrHeapLimit         Error = C.PCRE2_ERROR_HEAPLIMIT
+	ErrUnset             Error = C.PCRE2_ERROR_UNSET
+	ErrBadReplacement    Error = C.PCRE2_ERROR_BADREPLACEMENT
+)
+
+// The codes libpcre returns that libpcre2 has no counterpart for. They keep
+// code written for either backend building with both, but nothing returns
+// them with the pcre2 build tag. Their values lie in the ranges Error
+// reserves, see there.
+const (
+	ErrUnknownOpcode  Error = -3005
+	ErrUnknownNode    Error = ErrUnknownOpcode // For backwards compatibility
+	ErrBadPartial     Error = -3013
+	ErrBadCount       Error = -3015
+	ErrDFAUMLimit     Error = -3018
+	ErrNullWSLimit    Error = -3022 // No longer used
+	ErrBadNewline     Error = -3023
+	ErrBadEndianness  Error = -3029
+	ErrBadUTF16       Error = -1010
+	ErrBadUTF16Offset Error = -1011
+	ErrShortUTF16     Error = -1025
+	ErrBadUTF32       Error = -2010
+)
+
+// Codes of CompileError for some common mistakes, see pcre2api(3). They
+// differ from the libpcre numbers.
+const (
+	CompileErrEndBackslash         = C.PCRE2_ERROR_END_BACKSLASH
+	CompileErrMissingSquareBracket = C.PCRE2_ERROR_MISSING_SQUARE_BRACKET
+	CompileErrRangeOutOfOrder      = C.PCRE2_ERROR_CLASS_RANGE_ORDER
+	CompileErrNothingToRepeat      = C.PCRE2_ERROR_QUANTIFIER_INVALID
+	CompileErrMissingParenthesis   = C.PCRE2_ERROR_MISSING_CLOSING_PARENTHESIS
+	CompileErrUnmatchedParenthesis = C.PCRE2_ERROR_UNMATCHED_CLOSING_PARENTHESIS
+)
+
+var errorText = map[Error]string{
+	ErrNoMatch:           "no match",
+	ErrNull:              "NULL argument",
+	ErrBadOption:         "unrecognized option",
+	ErrBadMagic:          "bad magic number in compiled pattern",
+	ErrNoMemory:          "out of memory",
+	ErrNoSubstring:       "no such captured substring",
+	ErrNoUniqueSubstring: "subpattern name is not unique",
+	ErrMatchLimit:        "match limit exceeded",
+	ErrCallout:           "callout failed",
+	ErrBadUTF8:           "invalid UTF-8 in subject",
+	ErrBadUTF8Offset:     "start offset is not at the start of a UTF-8 character",
+	ErrPartial:           "partial match",
+	ErrInternal:          "internal error",
+	ErrDFAUItem:          "item not supported by DFA matching",
+	ErrDFAUCond:          "condition not supported by DFA matching",
+	ErrDFAWSSize:         "DFA workspace too small",
+	ErrDFARecurse:        "DFA recursion workspace too small",
+	ErrRecursionLimit:    "recursion limit exceeded",
+	ErrBadOffset:         "start offset out of range",
+	ErrRecurseLoop:       "recursion loop without advancing in subject",
+	ErrJITStackLimit:     "JIT stack limit exceeded",
+	ErrBadMode:           "pattern compiled in a different 8/16/32 bit mode",
+	ErrDFABadRestart:     "DFA restart without matching previous partial match",
+	ErrJITBadOption:      "JIT not supported or not compiled for this mode",
+	ErrHeapLimit:         "heap limit exceeded",
+	ErrUnset:             "requested value is not set",
+	ErrBadReplacement:    "bad replacement string",
+	ErrUnknownOpcode:     "unknown opcode in compiled pattern",
+	ErrBadPartial:        "pattern contains items not supported for partial matching",
+	ErrBadCount:          "negative output vector size",
+	ErrDFAUMLimit:        "match limits not supported by DFA matching",
+	ErrNullWSLimit:       "workspace limit exceeded",
+	ErrBadNewline:        "invalid newline option",
+	ErrBadEndianness:     "pattern compiled with a different byte order",
+	ErrBadUTF16:          "invalid UTF-16 in subject",
+	ErrBadUTF16Offset:    "start offset is not at the start of a UTF-16 character",
+	ErrShortUTF16:        "incomplete UTF-16 character at end of subject",
+	ErrBadUTF32:          "invalid UTF-32 in subject",
+}
+
+// errorMessage returns the text libpcre2 has for a compile error code.
+func errorMessage(code C.int) string {
+	var buf [256]byte
+	if n := C.pcre2_get_error_message_8(code, (*C.uchar)(unsafe.Pointer(&buf[0])), C.size_t(len(buf))); n > 0 {
+		return string(buf[:n])
+	}
+	return "unknown compile error"
+}
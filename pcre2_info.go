@@ -0,0 +1,218 @@
+//go:build pcre2
+// +build pcre2
+
+package pcre
+
+// #define PCRE2_CODE_UNIT_WIDTH 8
+// #include <pcre2.h>
+import "C"
+
+import (
+	"unicode/utf8"
+	"unsafe"
+)
+
+type Info int
+
+const (
+	InfoAllOptions    = C.PCRE2_INFO_ALLOPTIONS
+	InfoArgOptions    = C.PCRE2_INFO_ARGOPTIONS
+	InfoBackrefMax    = C.PCRE2_INFO_BACKREFMAX
+	InfoBSR           = C.PCRE2_INFO_BSR
+	InfoCaptureCount  = C.PCRE2_INFO_CAPTURECOUNT
+	InfoHasCRorLF     = C.PCRE2_INFO_HASCRORLF
+	InfoJchanged      = C.PCRE2_INFO_JCHANGED
+	InfoJITSize       = C.PCRE2_INFO_JITSIZE
+	InfoMatchEmpty    = C.PCRE2_INFO_MATCHEMPTY
+	InfoMaxLookBehind = C.PCRE2_INFO_MAXLOOKBEHIND
+	InfoMinLength     = C.PCRE2_INFO_MINLENGTH
+	InfoNameCount     = C.PCRE2_INFO_NAMECOUNT
+	InfoNameEntrySize = C.PCRE2_INFO_NAMEENTRYSIZE
+	InfoNameTable     = C.PCRE2_INFO_NAMETABLE
+	InfoNewline       = C.PCRE2_INFO_NEWLINE
+	InfoSize          = C.PCRE2_INFO_SIZE
+	InfoOptions       = InfoAllOptions
+	InfoFirstByte     = C.PCRE2_INFO_FIRSTCODEUNIT
+	InfoFirstChar     = InfoFirstByte
+	InfoFirstTable    = C.PCRE2_INFO_FIRSTBITMAP
+	InfoLastLiteral   = C.PCRE2_INFO_LASTCODEUNIT
+
+	// libpcre2 keeps no study data and always allows partial matching.
+	InfoStudySize     = infoUnsupported
+	InfoDefaultTables = infoUnsupported
+	InfoOkPartial     = infoUnsupported
+	InfoJIT           = infoUnsupported
+)
+
+// Further information pcre2_pattern_info has, PatternInfo decodes them.
+const (
+	infoFirstCodeType = C.PCRE2_INFO_FIRSTCODETYPE
+	infoLastCodeType  = C.PCRE2_INFO_LASTCODETYPE
+)
+
+// Info returns the information pcre2_pattern_info has on pcre. PCRE2 keeps
+// everything in the compiled pattern, so extra is not used.
+func (pcre *PCRE) Info(extra *PCREExtra) (*PatternInfo, error) {
+	var (
+		options, captureCount, backrefMax   C.uint32_t
+		nameEntrySize, nameCount, minLength C.uint32_t
+		maxLookBehind, matchEmpty, jChanged C.uint32_t
+		hasCRorLF                           C.uint32_t
+		firstCodeType, firstCodeUnit        C.uint32_t
+		lastCodeType, lastCodeUnit          C.uint32_t
+		size, jitSize                       C.size_t
+		firstBitmap                         *C.uint8_t
+	)
+
+	for _, query := range []struct {
+		what  Info
+		where unsafe.Pointer
+	}{
+		{InfoAllOptions, unsafe.Pointer(&options)},
+		{InfoSize, unsafe.Pointer(&size)},
+		{InfoCaptureCount, unsafe.Pointer(&captureCount)},
+		{InfoBackrefMax, unsafe.Pointer(&backrefMax)},
+		{infoFirstCodeType, unsafe.Pointer(&firstCodeType)},
+		{InfoFirstByte, unsafe.Pointer(&firstCodeUnit)},
+		{InfoFirstTable, unsafe.Pointer(&firstBitmap)},
+		{infoLastCodeType, unsafe.Pointer(&lastCodeType)},
+		{InfoLastLiteral, unsafe.Pointer(&lastCodeUnit)},
+		{InfoNameEntrySize, unsafe.Pointer(&nameEntrySize)},
+		{InfoNameCount, unsafe.Pointer(&nameCount)},
+		{InfoMinLength, unsafe.Pointer(&minLength)},
+		{InfoMaxLookBehind, unsafe.Pointer(&maxLookBehind)},
+		{InfoMatchEmpty, unsafe.Pointer(&matchEmpty)},
+		{InfoJchanged, unsafe.Pointer(&jChanged)},
+		{InfoHasCRorLF, unsafe.Pointer(&hasCRorLF)},
+		{InfoJITSize, unsafe.Pointer(&jitSize)},
+	} {
+		if err := pcre.fullinfo(extra, query.what, query.where); err != nil {
+			return nil, err
+		}
+	}
+
+	names, err := pcre.names()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PatternInfo{
+		Options:       CompileOption(options),
+		Size:          int(size),
+		CaptureCount:  int(captureCount),
+		BackrefMax:    int(backrefMax),
+		FirstByte:     -2,
+		LastLiteral:   -1,
+		NameEntrySize: int(nameEntrySize),
+		NameCount:     int(nameCount),
+		Names:         names,
+		MinLength:     int(minLength),
+		MaxLookBehind: int(maxLookBehind),
+		MatchEmpty:    matchEmpty != 0,
+		OkPartial:     true,
+		JChanged:      jChanged != 0,
+		HasCRorLF:     hasCRorLF != 0,
+		JIT:           jitSize > 0,
+		JITSize:       int(jitSize),
+	}
+	// The code types tell a known first or last code unit, 1, from a match
+	// at the start of a line, 2, and neither, 0. libpcre has no first byte
+	// for anchored patterns, which libpcre2 marks Anchored in the options.
+	switch {
+	case options&Anchored != 0:
+	case firstCodeType == 1:
+		info.FirstByte = int(firstCodeUnit)
+	case firstCodeType == 2:
+		info.FirstByte = -1
+	}
+	if lastCodeType == 1 {
+		info.LastLiteral = int(lastCodeUnit)
+	}
+	if firstBitmap != nil {
+		info.FirstTable = C.GoBytes(unsafe.Pointer(firstBitmap), 256/8)
+	}
+	return info, nil
+}
+
+// fullinfo stores the information about pcre asked for by what in where.
+func (pcre *PCRE) fullinfo(_ *PCREExtra, what Info, where unsafe.Pointer) error {
+	if what == infoUnsupported {
+		return ErrBadOption
+	}
+	if rc := C.pcre2_pattern_info_8(pcre.ptr(), C.uint32_t(what), where); rc != 0 {
+		return Error(rc)
+	}
+	return nil
+}
+
+// StringNumber returns the number of the subpattern called name. When
+// several subpatterns share the name, which DupNames allows, it returns
+// ErrNoUniqueSubstring; use StringNumbers to get all. It returns
+// ErrNoSubstring if there is no subpattern called name.
+func (pcre *PCRE) StringNumber(name string) (int, error) {
+	nameCStr := C.CString(name)
+	defer C.free(unsafe.Pointer(nameCStr))
+
+	n := C.pcre2_substring_number_from_name_8(pcre.ptr(), (*C.uchar)(unsafe.Pointer(nameCStr)))
+	if n < 0 {
+		return 0, Error(n)
+	}
+	return int(n), nil
+}
+
+// StringNumbers returns the numbers of all subpatterns called name, in
+// increasing order. It returns ErrNoSubstring if there are none.
+func (pcre *PCRE) StringNumbers(name string) ([]int, error) {
+	nameCStr := C.CString(name)
+	defer C.free(unsafe.Pointer(nameCStr))
+
+	var first, last C.PCRE2_SPTR8
+	entrySize := C.pcre2_substring_nametable_scan_8(pcre.ptr(), (*C.uchar)(unsafe.Pointer(nameCStr)), &first, &last)
+	if entrySize < 0 {
+		return nil, Error(entrySize)
+	}
+
+	count := int((uintptr(unsafe.Pointer(last))-uintptr(unsafe.Pointer(first)))/uintptr(entrySize)) + 1
+	entries := unsafe.Slice((*byte)(unsafe.Pointer(first)), count*int(entrySize))
+	numbers := make([]int, count)
+	for i := range numbers {
+		entry := entries[i*int(entrySize):]
+		numbers[i] = int(entry[0])<<8 | int(entry[1])
+	}
+	return numbers, nil
+}
+
+// Footprint returns the number of bytes held by the compiled pattern and its
+// JIT compiled code. extra is not used.
+func (pcre *PCRE) Footprint(extra *PCREExtra) (int, error) {
+	info, err := pcre.Info(extra)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size + info.JITSize, nil
+}
+
+// partialStart returns where a partial match in oVector begins and the
+// earliest offset it depends on. pcre2_match only reports the start of the
+// match, the longest lookbehind of the pattern is counted back from there.
+func (pcre *PCRE) partialStart(extra *PCREExtra, subject string, oVector []int) (start, keep int, err error) {
+	info, err := pcre.Info(extra)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The lookbehind is counted in characters, which in UTF-8 mode may
+	// each be several bytes.
+	start, keep = oVector[0], oVector[0]
+	if info.Options&UTF8 == 0 {
+		if keep -= info.MaxLookBehind; keep < 0 {
+			keep = 0
+		}
+		return start, keep, nil
+	}
+	for n := 0; n < info.MaxLookBehind && keep > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(subject[:keep])
+		keep -= size
+	}
+	return start, keep, nil
+}
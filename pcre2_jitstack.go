@@ -0,0 +1,31 @@
+//go:build pcre2
+// +build pcre2
+
+package pcre
+
+// #define PCRE2_CODE_UNIT_WIDTH 8
+// #include <pcre2.h>
+import "C"
+
+import "unsafe"
+
+// JITStack is a stack for running JIT compiled patterns on. Without one, the
+// JIT uses 32K of machine stack, and deeply backtracking patterns or large
+// subjects fail with ErrJITStackLimit.
+type JITStack C.pcre2_jit_stack_8
+
+// NewJITStack allocates a JIT stack that starts at startSize bytes and may
+// grow up to maxSize bytes. Release it with Free.
+func NewJITStack(startSize, maxSize int) (*JITStack, error) {
+	stack := C.pcre2_jit_stack_create_8(C.size_t(startSize), C.size_t(maxSize), nil)
+	if stack == nil {
+		return nil, ErrNoMemory
+	}
+	return (*JITStack)(unsafe.Pointer(stack)), nil
+}
+
+func (stack *JITStack) Free() { C.pcre2_jit_stack_free_8(stack.ptr()) }
+
+func (stack *JITStack) ptr() *C.pcre2_jit_stack_8 {
+	return (*C.pcre2_jit_stack_8)(unsafe.Pointer(stack))
+}
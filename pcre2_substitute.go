@@ -0,0 +1,58 @@
+//go:build pcre2
+// +build pcre2
+
+package pcre
+
+// #define PCRE2_CODE_UNIT_WIDTH 8
+// #include <pcre2.h>
+//
+// int go_pcre2_match_context(uint32_t matchLimit, uint32_t depthLimit, pcre2_jit_stack *stack, pcre2_match_context **mcontext);
+//
+// static int go_pcre2_substitute(const pcre2_code *code, const unsigned char *subject, size_t length, size_t startOffset, uint32_t options, const unsigned char *replacement, size_t replacementLength, uint32_t matchLimit, uint32_t depthLimit, pcre2_jit_stack *stack, unsigned char *out, size_t *outLength) {
+//     pcre2_match_context *mcontext;
+//
+//     if (!go_pcre2_match_context(matchLimit, depthLimit, stack, &mcontext)) {
+//         return PCRE2_ERROR_NOMEMORY;
+//     }
+//     return pcre2_substitute(code, subject, length, startOffset, options | PCRE2_SUBSTITUTE_OVERFLOW_LENGTH, NULL, mcontext, replacement, replacementLength, out, outLength);
+// }
+import "C"
+
+import "unsafe"
+
+// Options for Substitute, in addition to the match options.
+const (
+	SubstituteGlobal       ExecOption = C.PCRE2_SUBSTITUTE_GLOBAL        // Replace every match instead of only the first
+	SubstituteExtended     ExecOption = C.PCRE2_SUBSTITUTE_EXTENDED      // Backslash escapes, ${n:+set:unset} and \U, \L case forcing
+	SubstituteUnsetEmpty   ExecOption = C.PCRE2_SUBSTITUTE_UNSET_EMPTY   // Unset groups insert nothing instead of failing with ErrUnset
+	SubstituteUnknownUnset ExecOption = C.PCRE2_SUBSTITUTE_UNKNOWN_UNSET // References to unknown groups count as unset
+)
+
+// Substitute replaces the first match of the pattern in subject at or after
+// startOffset with replacement, or every match with SubstituteGlobal, using
+// pcre2_substitute. In replacement $n, ${n} and ${name} insert a captured
+// group and $$ a dollar sign. It returns the new subject and the number of
+// replacements made, which is 0, with subject unchanged, when there is no
+// match. The limits and JIT stack of extra apply.
+func (pcre *PCRE) Substitute(extra *PCREExtra, subject string, startOffset int, options ExecOption, replacement string) (string, int, error) {
+	limits, stack := extra.settings(nil)
+
+	// The first try guesses the size of the result, if that is too small
+	// pcre2_substitute reports the size needed.
+	out := make([]byte, len(subject)+len(replacement)+1)
+	for {
+		outLength := C.size_t(len(out))
+		rc := C.go_pcre2_substitute(pcre.ptr(), (*C.uchar)(unsafe.Pointer(subjectPtr(subject))), C.size_t(len(subject)), C.size_t(startOffset),
+			C.uint32_t(options), (*C.uchar)(unsafe.Pointer(subjectPtr(replacement))), C.size_t(len(replacement)),
+			C.uint32_t(limits.Match), C.uint32_t(limits.Recursion), stack.ptr(), (*C.uchar)(unsafe.Pointer(&out[0])), &outLength)
+
+		switch {
+		case rc >= 0:
+			return string(out[:outLength]), int(rc), nil
+		case Error(rc) == ErrNoMemory && int(outLength) > len(out):
+			out = make([]byte, outLength)
+		default:
+			return "", 0, Error(rc)
+		}
+	}
+}
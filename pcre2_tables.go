@@ -0,0 +1,84 @@
+//go:build pcre2
+// +build pcre2
+
+package pcre
+
+// #define PCRE2_CODE_UNIT_WIDTH 8
+// #include <pcre2.h>
+// #include <locale.h>
+// #include <string.h>
+// #ifdef __APPLE__
+// #include <xlocale.h>
+// #endif
+//
+// // Builds the tables under name for the calling thread only, so other
+// // threads keep their locale.
+// static const unsigned char *maketables_locale(const char *name) {
+//     const unsigned char *tables;
+//     locale_t old, loc = newlocale(LC_CTYPE_MASK, name, (locale_t)0);
+//     if (loc == (locale_t)0) {
+//         return NULL;
+//     }
+//     old = uselocale(loc);
+//     tables = pcre2_maketables(NULL);
+//     uselocale(old);
+//     freelocale(loc);
+//     return tables;
+// }
+//
+// // Tables made by pcre2_maketables come from the default allocator, so
+// // the ones built in Go are allocated the same way.
+// static unsigned char *copy_tables(const void *data, size_t size) {
+//     unsigned char *tables = malloc(size);
+//     if (tables != NULL) {
+//         memcpy(tables, data, size);
+//     }
+//     return tables;
+// }
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// Tables are the character tables that decide case folding and what \d, \s,
+// \w and the POSIX classes match for characters below 256. Patterns use the
+// tables they were compiled with, which are kept alive as long as any such
+// pattern is, and released by the garbage collector after that.
+type Tables struct {
+	ptr *C.uchar
+}
+
+func newTables(ptr *C.uchar) *Tables {
+	tables := &Tables{ptr: ptr}
+	runtime.SetFinalizer(tables, func(tables *Tables) {
+		C.free(unsafe.Pointer(tables.ptr))
+	})
+	return tables
+}
+
+// NewTables builds tables for the character classes of locale, such as
+// "de_DE.ISO-8859-1", using pcre2_maketables.
+func NewTables(locale string) (*Tables, error) {
+	name := C.CString(locale)
+	defer C.free(unsafe.Pointer(name))
+
+	ptr := C.maketables_locale(name)
+	if ptr == nil {
+		return nil, fmt.Errorf("pcre: cannot build tables for locale %q", locale)
+	}
+	return newTables((*C.uchar)(unsafe.Pointer(ptr))), nil
+}
+
+// NewTablesFromClasses builds tables for the character classes defined by
+// classes, the same way pcre2_maketables does from the C locale functions.
+func NewTablesFromClasses(classes CharClasses) *Tables {
+	data := tablesFromClasses(classes)
+	ptr := C.copy_tables(unsafe.Pointer(&data[0]), tablesLength)
+	if ptr == nil {
+		panic(ErrNoMemory)
+	}
+	return newTables(ptr)
+}
@@ -0,0 +1,105 @@
+//go:build pcre32 && !pcre2
+// +build pcre32,!pcre2
+
+package pcre
+
+// #cgo LDFLAGS: -lpcre32
+// #include <pcre.h>
+//
+// static void call_pcre32_free(void *ptr) {
+//     pcre32_free(ptr);
+// }
+import "C"
+
+import (
+	"unsafe"
+)
+
+func init() {
+	utf32Config = func() bool {
+		var value C.int
+		return C.pcre32_config(C.PCRE_CONFIG_UTF32, unsafe.Pointer(&value)) == 0 && value == 1
+	}
+}
+
+// PCRE32 is a pattern compiled by the 32-bit library, libpcre32, which
+// matches subjects made of 32-bit code units such as UTF-32 text. It is only
+// available when building with the pcre32 tag.
+type PCRE32 C.struct_real_pcre32
+
+// PCRE32Extra holds the study data of a PCRE32.
+type PCRE32Extra C.struct_pcre32_extra
+
+// Compile32 compiles expr with options for the 32-bit library. Pass UTF32
+// to have subjects checked as UTF-32 and to enable UTF semantics. The
+// Offset of a returned CompileError is a byte offset in expr, like for
+// Compile.
+func Compile32(expr string, options CompileOption) (*PCRE32, error) {
+	var (
+		errCode   C.int
+		errPtr    *C.char
+		errOffset C.int
+	)
+
+	pattern := append([]rune(expr), 0)
+
+	re := C.pcre32_compile2((*C.uint)(unsafe.Pointer(&pattern[0])), C.int(options), &errCode, &errPtr, &errOffset, nil)
+	if re == nil {
+		return nil, wideCompileError(expr, errCode, errPtr, errOffset, func(rune) int { return 1 })
+	}
+	track(unsafe.Pointer(re))
+	return (*PCRE32)(unsafe.Pointer(re)), nil
+}
+
+// Study32 is Study for patterns compiled by Compile32.
+func Study32(code *PCRE32, options StudyOption) (*PCRE32Extra, error) {
+	var errPtr *C.char
+
+	extra := C.pcre32_study(code.ptr(), C.int(options), &errPtr)
+	if err := wideStudied(unsafe.Pointer(extra), errPtr); err != nil {
+		return nil, err
+	}
+	return (*PCRE32Extra)(unsafe.Pointer(extra)), nil
+}
+
+// Free releases the study data. Freeing an extra again does nothing.
+func (pcreExtra *PCRE32Extra) Free() {
+	if !untrack(unsafe.Pointer(pcreExtra)) {
+		return
+	}
+	C.pcre32_free_study((*C.pcre32_extra)(unsafe.Pointer(pcreExtra)))
+}
+
+// Free releases the compiled pattern. Freeing a pattern again does nothing.
+func (pcre *PCRE32) Free() {
+	if !untrack(unsafe.Pointer(pcre)) {
+		return
+	}
+	C.call_pcre32_free(unsafe.Pointer(pcre))
+}
+
+func (pcre *PCRE32) ptr() *C.pcre32 { return (*C.pcre32)(unsafe.Pointer(pcre)) }
+
+// Exec matches subject against the pattern starting at startOffset. The
+// subject is read in place, and startOffset and the offsets stored in
+// oVector count 32-bit code units. Invalid UTF-32 is reported as
+// ErrBadUTF32.
+func (pcre *PCRE32) Exec(extra *PCRE32Extra, subject []rune, startOffset int, options ExecOption, oVector []int) Error {
+	// pcre32_exec rejects a nil subject even when it is empty.
+	var empty C.uint
+	subjectPtr := &empty
+	if len(subject) > 0 {
+		subjectPtr = (*C.uint)(unsafe.Pointer(&subject[0]))
+	}
+
+	return wideExec(oVector, utf32Errors, func(oVector *C.int, size C.int) C.int {
+		return C.pcre32_exec(pcre.ptr(), (*C.pcre32_extra)(unsafe.Pointer(extra)), subjectPtr, C.int(len(subject)), C.int(startOffset), C.int(options), oVector, size)
+	})
+}
+
+// CaptureCount returns the number of capturing subpatterns.
+func (pcre *PCRE32) CaptureCount() int {
+	var count C.int
+	rc := C.pcre32_fullinfo(pcre.ptr(), nil, C.PCRE_INFO_CAPTURECOUNT, unsafe.Pointer(&count))
+	return wideCaptureCount("pcre32_fullinfo", rc, count)
+}
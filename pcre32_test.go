@@ -0,0 +1,65 @@
+//go:build pcre32 && !pcre2
+// +build pcre32,!pcre2
+
+package pcre
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompile32(t *testing.T) {
+	// 𝄞 takes one code unit and four bytes.
+	_, err := Compile32("𝄞(", UTF32)
+	var compileErr *CompileError
+	if !errors.As(err, &compileErr) {
+		t.Fatalf("Compile32 of an unclosed group: %v", err)
+	}
+	if compileErr.Offset != len("𝄞(") {
+		t.Errorf("Offset = %d, want %d", compileErr.Offset, len("𝄞("))
+	}
+	if caret := compileErr.Caret(); caret != "𝄞(\n  ^" {
+		t.Errorf("Caret = %q", caret)
+	}
+}
+
+func TestExec32(t *testing.T) {
+	re, err := Compile32("é+", UTF32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer re.Free()
+
+	oVector := make([]int, 3)
+	if e := re.Exec(nil, []rune("𝄞éé"), 0, 0, oVector); e < 0 {
+		t.Fatalf("Exec = %v", e)
+	}
+	if !reflect.DeepEqual(oVector[:2], []int{1, 3}) {
+		t.Errorf("match at %v, want [1 3]", oVector[:2])
+	}
+
+	e := re.Exec(nil, []rune{'x', 0xdc00}, 0, 0, oVector)
+	if e != ErrBadUTF32 || e.Error() != "pcre: invalid UTF-32 in subject" {
+		t.Errorf("Exec of a surrogate = %v, want ErrBadUTF32", e)
+	}
+
+	if !Config().UTF32 {
+		t.Error("Config().UTF32 = false with libpcre32 linked")
+	}
+}
+
+func TestFree32(t *testing.T) {
+	re, err := Compile32("a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra, err := Study32(re, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra.Free()
+	extra.Free()
+	re.Free()
+	re.Free()
+}
@@ -1,4 +1,6 @@
+//go:build (!darwin || linux) && !pcre2
 // +build !darwin linux
+// +build !pcre2
 
 package pcre
 
@@ -10,100 +12,42 @@ package pcre
 import "C"
 
 import (
-	"log"
-	"reflect"
-	"unsafe"
 	"errors"
+	"unsafe"
 )
 
 type PCREExtra C.struct_pcre_extra
-func Study(code *PCRE, options Option, _ interface{}) (*PCREExtra, error) {
+
+func Study(code *PCRE, options StudyOption, _ interface{}) (*PCREExtra, error) {
 	var errPtr *C.char
 
 	extra := C.pcre_study((*C.struct_real_pcre)(code), C.int(options), &errPtr)
 	if errPtr != nil {
 		return nil, errors.New(C.GoString(errPtr))
 	}
-	return (*PCREExtra)(extra), nil
-}
-
-func (pcreExtra *PCREExtra) Free() { C.pcre_free_study((*C.struct_pcre_extra)(unsafe.Pointer(pcreExtra))) }
-
-func (pcre *PCRE) Free() { C.call_pcre_free(unsafe.Pointer(pcre)) }
-
-func (pcre *PCRE) Exec(extra interface{}, subject string, startOffset int, options Option, oVector []int) Error {
-	subjectCStr := C.CString(subject)
-	defer C.free(unsafe.Pointer(subjectCStr))
-
-	oVectorC := make([]C.int, len(oVector))
-	for n, i := range oVector {
-		oVectorC[n] = C.int(i)
-	}
-
-	var oVectorPtr *C.int
-	if len(oVector) > 0 {
-		oVectorPtr = &oVectorC[0]
+	if extra != nil {
+		// Lets ExecContext pick the JIT stack per call.
+		(*PCREExtra)(extra).AssignJITStack(nil)
+		holdJITModes((*PCREExtra)(extra), options)
+		track(unsafe.Pointer(extra))
 	}
-
-	r := C.pcre_exec((*C.struct_real_pcre)(pcre), nil, subjectCStr, C.int(len(subject)), C.int(startOffset), C.int(options), oVectorPtr, C.int(len(oVector)))
-
-	for n, i := range oVectorC {
-		oVector[n] = int(i)
-	}
-
-	return Error(r)
-}
-
-func (pcre *PCRE) CaptureCount() int {
-	var i C.int
-	if rc := C.pcre_fullinfo((*C.struct_real_pcre)(pcre), nil, InfoCaptureCount, unsafe.Pointer(&i)); rc != 0 {
-		log.Panicf("pcre_fullinfo: %v", rc)
-	}
-	return int(i)
+	return (*PCREExtra)(extra), nil
 }
 
-func (pcre *PCRE) NameCount() int {
-	var i C.int
-	if rc := C.pcre_fullinfo((*C.struct_real_pcre)(pcre), nil, InfoNameCount, unsafe.Pointer(&i)); rc != 0 {
-		log.Panicf("pcre_fullinfo: %v", rc)
+// Free releases the study data. Freeing an extra again does nothing.
+func (pcreExtra *PCREExtra) Free() {
+	if !untrack(unsafe.Pointer(pcreExtra)) {
+		return
 	}
-	return int(i)
+	releaseJITModes(pcreExtra)
+	C.pcre_free_study((*C.struct_pcre_extra)(unsafe.Pointer(pcreExtra)))
 }
 
-func (pcre *PCRE) NameEntrySize() int {
-	var i C.int
-	if rc := C.pcre_fullinfo((*C.struct_real_pcre)(pcre), nil, InfoNameEntrySize, unsafe.Pointer(&i)); rc != 0 {
-		log.Panicf("pcre_fullinfo: %v", rc)
+// Free releases the compiled pattern. Freeing a pattern again does nothing.
+func (pcre *PCRE) Free() {
+	if !untrack(unsafe.Pointer(pcre)) {
+		return
 	}
-	return int(i)
+	releaseTables(pcre)
+	C.call_pcre_free(unsafe.Pointer(pcre))
 }
-
-func (pcre *PCRE) NameTable() []string {
-	names := make([]string, pcre.CaptureCount()+1)
-	if pcre.NameCount() == 0 {
-		return names
-	}
-
-	var dataPtr uintptr
-	if rc := C.pcre_fullinfo((*C.struct_real_pcre)(pcre), nil, InfoNameTable, unsafe.Pointer(&dataPtr)); rc != 0 {
-		log.Panicf("pcre_fullinfo: %v", rc)
-	}
-
-	var data = *(*[]byte)(unsafe.Pointer(
-		&reflect.SliceHeader{
-			Data: dataPtr,
-			Len:  pcre.NameCount() * pcre.NameEntrySize(),
-			Cap:  pcre.NameCount() * pcre.NameEntrySize(),
-		}))
-
-	for i := 0; i < len(data); {
-		n := (int(data[i]) << 8) | int(data[i+1])
-		s := string(data[i+2 : i+pcre.NameEntrySize()-1])
-
-		names[n] = s
-
-		i += pcre.NameEntrySize()
-	}
-
-	return names
-}
\ No newline at end of file
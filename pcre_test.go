@@ -0,0 +1,71 @@
+package pcre
+
+import "testing"
+
+// mustCompile compiles expr or fails the test, the pattern is freed when the
+// test ends.
+func mustCompile(t *testing.T, expr string, options CompileOption) *PCRE {
+	t.Helper()
+	re, err := Compile(expr, options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(re.Free)
+	return re
+}
+
+func TestUsesJITPartial(t *testing.T) {
+	re := mustCompile(t, `abc`, UTF8)
+
+	complete, err := Study(re, StudyJITCompile, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer complete.Free()
+	if !complete.UsesJIT(0) {
+		t.Skip("libpcre built without JIT support")
+	}
+	if complete.UsesJIT(PartialSoft) || complete.UsesJIT(PartialHard) {
+		t.Error("UsesJIT(Partial*) for a pattern studied with StudyJITCompile only")
+	}
+
+	soft, err := Study(re, StudyJITCompile|StudyJITPartialSoftCompile, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer soft.Free()
+	if !soft.UsesJIT(PartialSoft) {
+		t.Error("UsesJIT(PartialSoft) = false for a pattern studied with StudyJITPartialSoftCompile")
+	}
+	if soft.UsesJIT(PartialHard) {
+		t.Error("UsesJIT(PartialHard) = true for a pattern studied with StudyJITPartialSoftCompile")
+	}
+	if e := re.ExecJIT(soft, nil, "ab", 0, PartialHard, make([]int, 3)); e != ErrJITBadOption {
+		t.Errorf("ExecJIT with PartialHard = %v, want ErrJITBadOption", e)
+	}
+}
+
+func TestExecJITOffset(t *testing.T) {
+	re := mustCompile(t, `a`, UTF8)
+	extra, err := Study(re, StudyJITCompile, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer extra.Free()
+	if !extra.UsesJIT(0) {
+		t.Skip("libpcre built without JIT support")
+	}
+
+	oVector := make([]int, 3)
+	for _, offset := range []int{-1, 4, 1 << 20} {
+		if e := re.ExecJIT(extra, nil, "baa", offset, 0, oVector); e != ErrBadOffset {
+			t.Errorf("ExecJIT at offset %d = %v, want ErrBadOffset", offset, e)
+		}
+	}
+	if e := re.ExecJIT(extra, nil, "baa", 3, 0, oVector); e != ErrNoMatch {
+		t.Errorf("ExecJIT at the end of the subject = %v, want ErrNoMatch", e)
+	}
+	if e := re.ExecJIT(extra, nil, "baa", 2, 0, oVector); e < 0 || oVector[0] != 2 {
+		t.Errorf("ExecJIT at offset 2 = %v with match at %d, want a match at 2", e, oVector[0])
+	}
+}
@@ -0,0 +1,71 @@
+//go:build (pcre16 || pcre32) && !pcre2
+// +build pcre16 pcre32
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+import "C"
+
+import (
+	"errors"
+	"log"
+	"unsafe"
+)
+
+// The helpers below hold what PCRE16 and PCRE32 share. The two libraries
+// differ only in their function names and code unit types, which stay in
+// pcre16.go and pcre32.go.
+
+// wideCompileError describes a failed pcre16_compile2 or pcre32_compile2
+// call. errOffset counts code units, of which units(r) make up the rune r.
+func wideCompileError(expr string, errCode C.int, errPtr *C.char, errOffset C.int, units func(r rune) int) *CompileError {
+	return &CompileError{
+		Message: C.GoString(errPtr),
+		Code:    int(errCode),
+		Offset:  byteOffset(expr, int(errOffset), units),
+		Pattern: expr,
+	}
+}
+
+// wideStudied checks the outcome of pcre16_study or pcre32_study and tracks
+// the study data it returned, if any.
+func wideStudied(extra unsafe.Pointer, errPtr *C.char) error {
+	if errPtr != nil {
+		return errors.New(C.GoString(errPtr))
+	}
+	if extra != nil {
+		track(extra)
+	}
+	return nil
+}
+
+// wideExec calls exec, which runs pcre16_exec or pcre32_exec with the given
+// output vector, and copies the offsets back into oVector. The UTF-8 codes
+// the library reports for invalid subjects are replaced through errs.
+func wideExec(oVector []int, errs map[Error]Error, exec func(oVector *C.int, size C.int) C.int) Error {
+	oVectorC := getOVector(len(oVector))
+	defer putOVector(oVectorC)
+
+	var oVectorPtr *C.int
+	if len(oVector) > 0 {
+		oVectorPtr = &(*oVectorC)[0]
+	}
+
+	r := exec(oVectorPtr, C.int(len(oVector)))
+
+	copyOVector(oVector, *oVectorC)
+	if e, ok := errs[Error(r)]; ok {
+		return e
+	}
+	return Error(r)
+}
+
+// wideCaptureCount returns count once name, pcre16_fullinfo or
+// pcre32_fullinfo, has stored it, and panics if the call returned an error.
+func wideCaptureCount(name string, rc, count C.int) int {
+	if rc != 0 {
+		log.Panicf("%s: %v", name, Error(rc))
+	}
+	return int(count)
+}
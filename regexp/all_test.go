@@ -5,9 +5,14 @@
 package regexp
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/wrapp/go-pcre"
 )
 
 var goodRegex = []string{
@@ -30,21 +35,23 @@ var goodRegex = []string{
 	`\!\\`,
 }
 
-type stringError struct {
-	re  string
-	err string
+// The messages differ between libpcre and libpcre2, the codes are compared
+// instead.
+type codeError struct {
+	re   string
+	code int
 }
 
-var badRegex = []stringError{
-	{`*`, "nothing to repeat"},
-	{`+`, "nothing to repeat"},
-	{`?`, "nothing to repeat"},
-	{`(abc`, "missing )"},
-	{`abc)`, "unmatched parentheses"},
-	{`x[a-z`, "missing terminating ] for character class"},
-	{`[z-a]`, "range out of order in character class"},
-	{`abc\`, "\\ at end of pattern"},
-	{`a**`, "nothing to repeat"},
+var badRegex = []codeError{
+	{`*`, pcre.CompileErrNothingToRepeat},
+	{`+`, pcre.CompileErrNothingToRepeat},
+	{`?`, pcre.CompileErrNothingToRepeat},
+	{`(abc`, pcre.CompileErrMissingParenthesis},
+	{`abc)`, pcre.CompileErrUnmatchedParenthesis},
+	{`x[a-z`, pcre.CompileErrMissingSquareBracket},
+	{`[z-a]`, pcre.CompileErrRangeOutOfOrder},
+	{`abc\`, pcre.CompileErrEndBackslash},
+	{`a**`, pcre.CompileErrNothingToRepeat},
 	//{`a*+`, "invalid nested repetition operator: `*+`"},
 	//{`\x`, "invalid escape sequence: `\\x`"},
 }
@@ -77,8 +84,93 @@ func TestGoodCompileAndStudy(t *testing.T) {
 }
 
 func TestBadCompile(t *testing.T) {
-	for i := 0; i < len(badRegex); i++ {
-		compileTest(t, badRegex[i].re, badRegex[i].err)
+	for _, test := range badRegex {
+		_, err := Compile(test.re)
+		var cerr *pcre.CompileError
+		if !errors.As(err, &cerr) {
+			t.Errorf("compiling `%s`: got %v, want a *pcre.CompileError", test.re, err)
+		} else if cerr.Code != test.code {
+			t.Errorf("compiling `%s`: code %d (%s), want %d", test.re, cerr.Code, cerr.Message, test.code)
+		}
+	}
+}
+
+func TestCompileErrorOffset(t *testing.T) {
+	_, err := Compile(`ab(c`)
+	cerr, ok := err.(*pcre.CompileError)
+	if !ok {
+		t.Fatalf("Compile(`ab(c`) error = %#v, want *pcre.CompileError", err)
+	}
+	if cerr.Offset != 4 {
+		t.Errorf("Offset = %d, want 4", cerr.Offset)
+	}
+	if caret := cerr.Caret(); caret != "ab(c\n    ^" {
+		t.Errorf("Caret() = %q", caret)
+	}
+}
+
+func TestMatchLimit(t *testing.T) {
+	re := MustCompile(`(a+)+$`)
+	re.SetLimits(pcre.Limits{Match: 1000})
+	matched, err := re.TryMatchString(strings.Repeat("a", 30) + "b")
+	if matched || !errors.Is(err, pcre.ErrMatchLimit) {
+		t.Errorf("TryMatchString = %v, %v; want false, %v", matched, err, pcre.ErrMatchLimit)
+	}
+
+	// The methods without an error result panic with it.
+	for name, f := range map[string]func(string){
+		"FindAllString": func(s string) { re.FindAllString(s, -1) },
+		"CountString":   func(s string) { re.CountString(s) },
+	} {
+		func() {
+			defer func() {
+				if err, _ := recover().(error); !errors.Is(err, pcre.ErrMatchLimit) {
+					t.Errorf("%s panicked with %v, want %v", name, err, pcre.ErrMatchLimit)
+				}
+			}()
+			f(strings.Repeat("a", 30) + "b")
+		}()
+	}
+}
+
+func TestSetLimitsWhileMatching(t *testing.T) {
+	re := MustCompile(`(a+)+$`)
+	defer re.Close()
+	subject := strings.Repeat("a", 20) + "b"
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := re.TryMatchString(subject); err != nil && !errors.Is(err, pcre.ErrMatchLimit) {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		re.SetLimits(pcre.Limits{Match: 1000 * uint(i%2+1)})
+	}
+	wg.Wait()
+}
+
+var matchPartialTests = []struct {
+	pattern string
+	input   string
+	result  pcre.MatchResult
+}{
+	{`^\d{4}-\d{2}-\d{2}$`, "2018-09-28", pcre.MatchFull},
+	{`^\d{4}-\d{2}-\d{2}$`, "2018-0", pcre.MatchPartial},
+	{`^\d{4}-\d{2}-\d{2}$`, "2018/", pcre.MatchNone},
+}
+
+func TestMatchPartial(t *testing.T) {
+	for _, tc := range matchPartialTests {
+		if result := MustCompile(tc.pattern).MatchPartialString(tc.input); result != tc.result {
+			t.Errorf("%q.MatchPartialString(%q) = %v, want %v", tc.pattern, tc.input, result, tc.result)
+		}
 	}
 }
 
@@ -463,6 +555,270 @@ var splitTests = []struct {
 	{":x:y:z:", ":", -1, []string{"", "x", "y", "z", ""}},
 }
 
+func TestDupNames(t *testing.T) {
+	re := MustCompile(`(?<id>\d+)|(?<id>[a-f]+)`)
+	if indices := re.SubexpIndices("id"); !reflect.DeepEqual(indices, []int{1, 2}) {
+		t.Errorf("SubexpIndices(id) = %v, want [1 2]", indices)
+	}
+	if i := re.SubexpIndex("id"); i != 1 {
+		t.Errorf("SubexpIndex(id) = %d, want 1", i)
+	}
+	if i := re.SubexpIndex("nope"); i != -1 {
+		t.Errorf("SubexpIndex(nope) = %d, want -1", i)
+	}
+	if i := re.MatchedSubexpIndex("id", re.FindStringSubmatchIndex("beef")); i != 2 {
+		t.Errorf("MatchedSubexpIndex(id) = %d, want 2", i)
+	}
+	if s := re.ReplaceAllString("beef", "<${id}>"); s != "<beef>" {
+		t.Errorf("ReplaceAllString = %q, want %q", s, "<beef>")
+	}
+}
+
+func TestFindStringMark(t *testing.T) {
+	re := MustCompile(`(*MARK:digits)\d+|(*MARK:letters)[a-z]+`)
+	for _, tc := range []struct{ input, match, mark string }{
+		{"abc123", "abc", "letters"},
+		{"123abc", "123", "digits"},
+		{"---", "", ""},
+	} {
+		if match, mark := re.FindStringMark(tc.input); match != tc.match || mark != tc.mark {
+			t.Errorf("FindStringMark(%q) = %q, %q; want %q, %q", tc.input, match, mark, tc.match, tc.mark)
+		}
+	}
+}
+
+func TestFootprint(t *testing.T) {
+	re := MustCompile(`(\w+)@(\w+)\.com`)
+	compiled := re.Footprint()
+	if compiled <= 0 {
+		t.Fatalf("Footprint() = %d, want > 0", compiled)
+	}
+	if err := re.Study(); err != nil {
+		t.Fatal(err)
+	}
+	if studied := re.Footprint(); studied < compiled {
+		t.Errorf("Footprint() after Study = %d, want >= %d", studied, compiled)
+	}
+}
+
+func TestClose(t *testing.T) {
+	re := MustCompile(`a+`)
+	if !re.MatchString("caat") {
+		t.Fatalf("MatchString(%q) = false before Close", "caat")
+	}
+	re.Close()
+	re.Close()
+	if _, err := re.TryMatchString("caat"); !errors.Is(err, ErrClosed) {
+		t.Errorf("TryMatchString after Close: err = %v, want ErrClosed", err)
+	}
+}
+
+func TestCloseWhileMatching(t *testing.T) {
+	re := MustCompile(`(a|b)*c`)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := re.TryMatchString("ababababc"); err != nil {
+					if !errors.Is(err, ErrClosed) {
+						t.Error(err)
+					}
+					return
+				}
+			}
+		}()
+	}
+	re.Close()
+	wg.Wait()
+}
+
+func TestStudyWhileMatching(t *testing.T) {
+	re := MustCompile(`(a|b)*c`)
+	defer re.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if j == i {
+					if err := re.Study(); err != nil {
+						t.Error(err)
+					}
+				}
+				if !re.MatchString("ababababc") {
+					t.Errorf("MatchString(%q) = false", "ababababc")
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	extra := re.extra()
+	if extra == nil {
+		t.Fatal("no study data after Study")
+	}
+	if err := re.Study(); err != nil {
+		t.Fatal(err)
+	}
+	if re.extra() != extra {
+		t.Error("Study again replaced the study data")
+	}
+}
+
+func TestMatchAllocs(t *testing.T) {
+	re := MustCompile(`(a|b)*c`)
+	if err := re.Study(); err != nil {
+		t.Fatal(err)
+	}
+	b := []byte("ababababc")
+	re.Match(b)
+	if n := testing.AllocsPerRun(100, func() { re.Match(b) }); n != 0 {
+		t.Errorf("Match: %v allocations per run, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { re.FindIndex(b) }); n > 1 {
+		t.Errorf("FindIndex: %v allocations per run, want at most 1", n)
+	}
+}
+
+func TestMatchStrings(t *testing.T) {
+	re := MustCompile(`\d+`)
+	if err := re.Study(); err != nil {
+		t.Fatal(err)
+	}
+	subjects := []string{"abc", "a1", "", "22b", "x"}
+	for i := 0; i < 10; i++ {
+		subjects = append(subjects, subjects...)
+	}
+
+	for _, workers := range []int{1, 4} {
+		matched := re.MatchStringsParallel(subjects, workers)
+		indices := re.FindIndexBatchParallel(subjects, workers)
+		for i, s := range subjects {
+			if matched[i] != re.MatchString(s) {
+				t.Fatalf("%d workers: MatchStrings[%d] = %v for %q", workers, i, matched[i], s)
+			}
+			if want := re.FindStringIndex(s); !reflect.DeepEqual(indices[i], want) {
+				t.Fatalf("%d workers: FindIndexBatch[%d] = %v for %q, want %v", workers, i, indices[i], s, want)
+			}
+		}
+	}
+}
+
+func TestFindAllManyMatches(t *testing.T) {
+	re := MustCompile(`b*`)
+	s := strings.Repeat("ab", 150)
+	locs := re.FindAllStringIndex(s, -1)
+	if len(locs) != 151 {
+		t.Fatalf("FindAllStringIndex: %d matches, want 151", len(locs))
+	}
+	for i, loc := range locs {
+		want := []int{0, 0}
+		if i > 0 {
+			want = []int{2*i - 1, 2 * i}
+		}
+		if !reflect.DeepEqual(loc, want) {
+			t.Fatalf("match %d at %v, want %v", i, loc, want)
+		}
+	}
+	if n := len(re.FindAllStringIndex(s, 100)); n != 100 {
+		t.Errorf("FindAllStringIndex(s, 100): %d matches", n)
+	}
+}
+
+func TestCount(t *testing.T) {
+	for _, test := range findTests {
+		re := MustCompile(test.pat)
+		if n, want := re.CountString(test.text), len(re.FindAllStringIndex(test.text, -1)); n != want {
+			t.Errorf("CountString(%q) for `%s` = %d, want %d", test.text, test.pat, n, want)
+		}
+		if n, want := re.Count([]byte(test.text)), len(test.matches); n != want {
+			t.Errorf("Count(%q) for `%s` = %d, want %d", test.text, test.pat, n, want)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	options, err := pcre.ParseCompileOptions("imsxU")
+	if want := pcre.Caseless | pcre.Multiline | pcre.DotAll | pcre.Extended | pcre.UnGreedy; err != nil || options != want {
+		t.Errorf("ParseCompileOptions(%q) = %v, %v; want %v", "imsxU", options, err, want)
+	}
+	if _, err := pcre.ParseCompileOptions("iq"); err == nil {
+		t.Errorf("ParseCompileOptions(%q) succeeded", "iq")
+	}
+
+	for _, test := range []struct {
+		option fmt.Stringer
+		want   string
+	}{
+		{pcre.CompileOption(0), "0"},
+		{pcre.Caseless | pcre.UTF8, "Caseless|UTF8"},
+		{pcre.NewlineCRLF, "NewlineCRLF"},
+		{pcre.NotBOL | pcre.NotEmptyAtStart, "NotBOL|NotEmptyAtStart"},
+		{pcre.StudyJITCompile, "StudyJITCompile"},
+	} {
+		if s := test.option.String(); s != test.want {
+			t.Errorf("String() = %q, want %q", s, test.want)
+		}
+	}
+}
+
+func TestExecJIT(t *testing.T) {
+	re, err := pcre.Compile(`a+`, pcre.UTF8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer re.Free()
+	if e := re.ExecJIT(nil, nil, "caat", 0, 0, nil); e != pcre.ErrJITBadOption {
+		t.Errorf("ExecJIT without study: got %v, want ErrJITBadOption", e)
+	}
+
+	jit := MustCompile(`a+`)
+	if err := jit.Study(); err != nil {
+		t.Fatal(err)
+	}
+	if !jit.JIT() {
+		t.Skip("libpcre built without JIT support")
+	}
+	if loc := jit.FindStringIndex("caat"); !reflect.DeepEqual(loc, []int{1, 3}) {
+		t.Errorf("FindStringIndex = %v, want [1 3]", loc)
+	}
+	var utf8Err *pcre.UTF8Error
+	if _, err := jit.TryMatchString("a\xff"); !errors.As(err, &utf8Err) {
+		t.Errorf("TryMatchString on invalid UTF-8: err = %v, want *pcre.UTF8Error", err)
+	}
+}
+
+func TestCompileWithOptions(t *testing.T) {
+	re := MustCompileWithOptions(`^hello$`, Options{Flags: pcre.Caseless})
+	if !re.MatchString("HELLO") {
+		t.Errorf("Caseless: %q does not match HELLO", re)
+	}
+
+	re = MustCompileWithOptions(`^b`, Options{Flags: pcre.Multiline, Newline: pcre.NewlineCR})
+	if !re.MatchString("a\rb") {
+		t.Errorf("Multiline with NewlineCR: %q does not match a\\rb", re)
+	}
+	if re.MatchString("a\nb") {
+		t.Errorf("Multiline with NewlineCR: %q matches a\\nb", re)
+	}
+
+	re = MustCompileWithOptions(`a+`, Options{Study: true, NoJIT: true})
+	if re.JIT() {
+		t.Error("NoJIT: pattern was JIT compiled")
+	}
+	if loc := re.FindStringIndex("caat"); !reflect.DeepEqual(loc, []int{1, 3}) {
+		t.Errorf("FindStringIndex = %v, want [1 3]", loc)
+	}
+
+	if _, err := CompileWithOptions(`a(`, Options{Study: true}); err == nil {
+		t.Error("CompileWithOptions(`a(`) did not fail")
+	}
+}
+
 func TestSplit(t *testing.T) {
 	t.Skip("TODO(mro)")
 
@@ -0,0 +1,107 @@
+package regexp
+
+import (
+	"sync"
+
+	"github.com/wrapp/go-pcre"
+)
+
+// minBatchChunk is the fewest subjects worth handing to a goroutine of its
+// own, smaller batches are not split.
+const minBatchChunk = 64
+
+// MatchStrings reports for each of subjects whether it contains a match of
+// re. The subjects are matched in one call into libpcre, which is much
+// cheaper than calling MatchString for each when they are short. It panics
+// if matching fails, like MatchString.
+func (re *Regexp) MatchStrings(subjects []string) []bool {
+	return re.MatchStringsParallel(subjects, 1)
+}
+
+// MatchStringsParallel is like MatchStrings, but splits the subjects over up
+// to workers goroutines, each matching its share on its own JIT stack.
+func (re *Regexp) MatchStringsParallel(subjects []string, workers int) []bool {
+	results, _ := re.execBatch(subjects, workers)
+	matched := make([]bool, len(subjects))
+	for i, r := range results {
+		matched[i] = r >= 0
+	}
+	return matched
+}
+
+// FindIndexBatch returns for each of subjects the location of the leftmost
+// match of re, as FindStringIndex does, or nil if there is none. Like
+// MatchStrings it matches all subjects in one call into libpcre.
+func (re *Regexp) FindIndexBatch(subjects []string) [][]int {
+	return re.FindIndexBatchParallel(subjects, 1)
+}
+
+// FindIndexBatchParallel is like FindIndexBatch, but splits the subjects
+// over up to workers goroutines like MatchStringsParallel.
+func (re *Regexp) FindIndexBatchParallel(subjects []string, workers int) [][]int {
+	results, locs := re.execBatch(subjects, workers)
+	indices := make([][]int, len(subjects))
+	for i, r := range results {
+		if r >= 0 {
+			indices[i] = locs[2*i : 2*i+2 : 2*i+2]
+		}
+	}
+	return indices
+}
+
+// execBatch matches every subject, on up to workers goroutines, and returns
+// the result and match offsets of each. It panics if matching fails.
+func (re *Regexp) execBatch(subjects []string, workers int) ([]pcre.Error, []int) {
+	results := make([]pcre.Error, len(subjects))
+	locs := make([]int, 2*len(subjects))
+
+	if chunks := (len(subjects) + minBatchChunk - 1) / minBatchChunk; workers > chunks {
+		workers = chunks
+	}
+	if workers <= 1 {
+		if err := re.execChunk(subjects, results, locs); err != nil {
+			panic(err)
+		}
+	} else {
+		size := (len(subjects) + workers - 1) / workers
+		errs := make([]error, workers)
+		var wg sync.WaitGroup
+		for w := 0; w*size < len(subjects); w++ {
+			start, end := w*size, (w+1)*size
+			if end > len(subjects) {
+				end = len(subjects)
+			}
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				errs[w] = re.execChunk(subjects[start:end], results[start:end], locs[2*start:2*end])
+			}(w)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	for i, r := range results {
+		if r < 0 && r != pcre.ErrNoMatch {
+			panic(r.Detail(locs[2*i : 2*i+2]))
+		}
+	}
+	return results, locs
+}
+
+// execChunk matches subjects with a single ExecBatch.
+func (re *Regexp) execChunk(subjects []string, results []pcre.Error, locs []int) error {
+	var ctx pcre.MatchContext
+	extra, err := re.begin(&ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer re.end(&ctx)
+
+	re.pcre.ExecBatch(extra, &ctx, subjects, 0, results, locs)
+	return nil
+}
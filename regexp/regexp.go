@@ -8,10 +8,15 @@ package regexp
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"runtime"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+	"unsafe"
 
 	"github.com/wrapp/go-pcre"
 )
@@ -21,45 +26,262 @@ func MatchString(pattern string, s string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	defer re.Close()
 
-	if re.pcreExtra == nil {
-		if err := re.Study(); err != nil {
-			return false, err
-		}
+	if err := re.Study(); err != nil {
+		return false, err
 	}
 
-	if matched := re.MatchString(s); !matched {
-		return false, nil
-	} else {
-		return true, nil
-	}
+	return re.TryMatchString(s)
 }
 
+// DefaultLimits are the match limits given to every Regexp by Compile. They
+// guard against patterns that backtrack catastrophically, a match that
+// exceeds them fails with pcre.ErrMatchLimit or pcre.ErrRecursionLimit.
+var DefaultLimits pcre.Limits
+
+// jitStacks supplies the stacks that JIT compiled patterns run on, one per
+// running match, so that large subjects do not overflow the default 32K.
+var jitStacks = pcre.NewJITStackPool(32<<10, 1<<20)
+
+// ErrClosed is returned, or panicked with by the methods that do not return
+// errors, when a Regexp is used after Close.
+var ErrClosed = errors.New("regexp: use of closed Regexp")
+
 type Regexp struct {
 	expr      string
 	pcre      *pcre.PCRE
-	pcreExtra *pcre.PCREExtra
+	numSubexp int
+
+	// limits is the *pcre.Limits every match applies, replaced as a whole by
+	// SetLimits while matches may be running.
+	limits unsafe.Pointer
+
+	// pcreExtra is the *pcre.PCREExtra made by Study, nil until then. It is
+	// stored once and read atomically, as matches may run while Study does.
+	pcreExtra unsafe.Pointer
+	study     sync.Once
+	studyErr  error
+
+	// studyOptions are what Study passes to pcre.Study.
+	studyOptions pcre.StudyOption
+
+	// refs counts the calls using pcre and pcreExtra, plus one held by the
+	// Regexp itself until Close. The memory is freed when it drops to zero,
+	// so a Close or finalizer running during a match cannot pull it away.
+	refs   int64
+	closed int32
+}
+
+// Compile parses expr and returns a Regexp that can be matched against text.
+// A pattern that does not compile is reported as a *pcre.CompileError, which
+// carries the offset of the offending character in expr.
+func Compile(expr string) (*Regexp, error) { return CompileWithOptions(expr, Options{}) }
+
+// Options holds the settings for CompileWithOptions. The zero value compiles
+// like Compile.
+type Options struct {
+	// Flags are compile options such as pcre.Caseless, pcre.Multiline,
+	// pcre.DotAll, pcre.Extended, pcre.UnGreedy, pcre.UCP or pcre.FirstLine.
+	// pcre.ParseCompileOptions turns Perl-style letters like "imsx" into
+	// them. pcre.UTF8 and pcre.DupNames are always added.
+	Flags pcre.CompileOption
+
+	// Newline is the newline convention, one of the pcre.Newline* options,
+	// or 0 for the one libpcre was built with.
+	Newline pcre.CompileOption
+
+	// BSR is what \R matches, pcre.BSRAnyCRLF or pcre.BSRUnicode, or 0 for
+	// the libpcre default.
+	BSR pcre.CompileOption
+
+	// Study makes CompileWithOptions study the pattern right away, as the
+	// Study method does.
+	Study bool
+
+	// NoJIT leaves out JIT compiling when the pattern is studied, here or
+	// by the Study method, so that it always runs on the interpreter.
+	NoJIT bool
 }
 
-func Compile(expr string) (*Regexp, error) {
-	re, err := pcre.Compile(expr, pcre.UTF8|pcre.DupNames, nil)
+// CompileWithOptions is like Compile, but compiles expr with the settings
+// in options.
+func CompileWithOptions(expr string, options Options) (*Regexp, error) {
+	flags := options.Flags | options.Newline | options.BSR | pcre.UTF8 | pcre.DupNames
+	re, err := pcre.Compile(expr, flags, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	regexp := &Regexp{expr: expr, pcre: re}
-	runtime.SetFinalizer(regexp, func(re *Regexp) {
-		if re.pcreExtra != nil {
-			re.pcreExtra.Free()
+	limits := DefaultLimits
+	regexp := &Regexp{expr: expr, pcre: re, limits: unsafe.Pointer(&limits), numSubexp: re.CaptureCount(), studyOptions: pcre.StudyJITCompile, refs: 1}
+	if options.NoJIT {
+		regexp.studyOptions = 0
+	}
+	runtime.SetFinalizer(regexp, (*Regexp).Close)
+
+	if options.Study {
+		if err := regexp.Study(); err != nil {
+			regexp.Close()
+			return nil, err
+		}
+	}
+	return regexp, nil
+}
+
+// Close frees the memory libpcre holds for re, instead of leaving that to
+// the garbage collector. Matches still running on re complete first, the
+// memory is released when the last one returns. Using re after Close fails
+// with ErrClosed. Closing re again does nothing.
+func (re *Regexp) Close() error {
+	if atomic.CompareAndSwapInt32(&re.closed, 0, 1) {
+		runtime.SetFinalizer(re, nil)
+		re.release()
+	}
+	return nil
+}
+
+// acquire takes a reference to the memory of re for a call that uses it, it
+// fails once re is closed.
+func (re *Regexp) acquire() error {
+	for {
+		refs := atomic.LoadInt64(&re.refs)
+		if refs == 0 {
+			return ErrClosed
+		}
+		if atomic.CompareAndSwapInt64(&re.refs, refs, refs+1) {
+			return nil
+		}
+	}
+}
+
+// release drops a reference taken by acquire, or the one held by re itself.
+func (re *Regexp) release() {
+	if atomic.AddInt64(&re.refs, -1) == 0 {
+		if extra := re.extra(); extra != nil {
+			extra.Free()
 		}
 		re.pcre.Free()
+	}
+}
+
+// Study optimizes re for matching, JIT compiling it unless Options.NoJIT
+// was given. Only the first call studies the pattern, later ones return its
+// result. It is safe to call while re is matching on other goroutines.
+func (re *Regexp) Study() error {
+	if err := re.acquire(); err != nil {
+		return err
+	}
+	defer re.release()
+
+	re.study.Do(func() {
+		var extra *pcre.PCREExtra
+		if extra, re.studyErr = pcre.Study(re.pcre, re.studyOptions, nil); re.studyErr == nil {
+			atomic.StorePointer(&re.pcreExtra, unsafe.Pointer(extra))
+		}
 	})
-	return regexp, nil
+	return re.studyErr
+}
+
+// extra returns the study data of re, nil if it was not studied.
+func (re *Regexp) extra() *pcre.PCREExtra {
+	return (*pcre.PCREExtra)(atomic.LoadPointer(&re.pcreExtra))
+}
+
+// SetLimits replaces the match limits of re, which start out as
+// DefaultLimits. Matches already running keep the limits they started with.
+func (re *Regexp) SetLimits(limits pcre.Limits) {
+	atomic.StorePointer(&re.limits, unsafe.Pointer(&limits))
+}
+
+// begin prepares ctx for one match with options and keeps the memory of re
+// alive until end is called with the same ctx. It returns the study data the
+// match is to use, so that a concurrent Study cannot change it midway.
+func (re *Regexp) begin(ctx *pcre.MatchContext, options pcre.ExecOption) (*pcre.PCREExtra, error) {
+	if err := re.acquire(); err != nil {
+		return nil, err
+	}
+
+	ctx.Limits = *(*pcre.Limits)(atomic.LoadPointer(&re.limits))
+	extra := re.extra()
+	if !extra.UsesJIT(options) {
+		return extra, nil
+	}
+
+	stack, err := jitStacks.Get()
+	if err != nil {
+		re.release()
+		return nil, err
+	}
+	ctx.JITStack = stack
+	return extra, nil
+}
+
+// end finishes a match started by begin.
+func (re *Regexp) end(ctx *pcre.MatchContext) {
+	if ctx.JITStack != nil {
+		jitStacks.Put(ctx.JITStack)
+		ctx.JITStack = nil
+	}
+	re.release()
+}
+
+// exec runs the pattern over s from start, filling oVector. Not matching is
+// not an error.
+func (re *Regexp) exec(s string, start int, options pcre.ExecOption, oVector []int) (bool, error) {
+	var ctx pcre.MatchContext
+	extra, err := re.begin(&ctx, options)
+	if err != nil {
+		return false, err
+	}
+	defer re.end(&ctx)
+
+	// libpcre reports where and why a subject is invalid UTF-8 in the first
+	// offset pair, callers that want no offsets get a vector for that.
+	var scratch [3]int
+	if len(oVector) < 2 {
+		oVector = scratch[:]
+	}
+
+	var e pcre.Error
+	if ctx.JITStack != nil && ctx.Limits == (pcre.Limits{}) && (options&pcre.NoUTF8Check != 0 || utf8.ValidString(s)) {
+		// The JIT fast path leaves invalid UTF-8 undetected, such subjects
+		// take the checked path below to have it reported.
+		e = re.pcre.ExecJIT(extra, ctx.JITStack, s, start, options, oVector)
+	} else {
+		e = re.pcre.ExecContext(extra, &ctx, s, start, options, oVector)
+	}
+	if e == pcre.ErrNoMatch {
+		return false, nil
+	} else if e < 0 {
+		return false, e.Detail(oVector)
+	}
+	return true, nil
+}
+
+// JIT reports whether matches run on JIT compiled code, which is the case
+// once Study succeeded on a libpcre built with JIT support.
+func (re *Regexp) JIT() bool {
+	if re.acquire() != nil {
+		return false
+	}
+	defer re.release()
+	return re.extra().UsesJIT(0)
 }
 
-func (re *Regexp) Study() (err error) {
-	re.pcreExtra, err = pcre.Study(re.pcre, pcre.StudyJITCompile, nil)
-	return
+// Footprint returns the number of bytes of C memory held by re, which the
+// Go heap profiler does not see. It is 0 once re is closed.
+func (re *Regexp) Footprint() int {
+	if re.acquire() != nil {
+		return 0
+	}
+	defer re.release()
+
+	n, err := re.pcre.Footprint(re.extra())
+	if err != nil {
+		panic(err)
+	}
+	return n
 }
 
 func CompilePOSIX(_ string) (*Regexp, error) {
@@ -78,6 +300,16 @@ func MustCompile(str string) *Regexp {
 	}
 }
 
+// MustCompileWithOptions is like CompileWithOptions but panics if expr does
+// not compile.
+func MustCompileWithOptions(expr string, options Options) *Regexp {
+	re, err := CompileWithOptions(expr, options)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
 func MustCompilePOSIX(str string) *Regexp {
 	if re, err := CompilePOSIX(str); err != nil {
 		panic(err)
@@ -87,21 +319,49 @@ func MustCompilePOSIX(str string) *Regexp {
 }
 
 func (re *Regexp) Match(b []byte) bool {
-	return re.MatchString(string(b))
+	return re.MatchString(bytesString(b))
 }
 
+// MatchString reports whether s contains any match of re. It panics if
+// matching fails, for instance because a limit was exceeded; use
+// TryMatchString to get such failures as an error.
 func (re *Regexp) MatchString(s string) bool {
-	var extra interface{} = nil
-	if re.pcreExtra != nil {
-		extra = re.pcreExtra
+	matched, err := re.TryMatchString(s)
+	if err != nil {
+		panic(err)
 	}
-	if err := re.pcre.Exec(extra, s, 0, 0, nil); err == pcre.ErrNoMatch {
-		return false
-	} else if err < 0 {
-		panic("dont know what to do")
+	return matched
+}
+
+// TryMatch is like Match but returns matching failures as an error.
+func (re *Regexp) TryMatch(b []byte) (bool, error) { return re.TryMatchString(bytesString(b)) }
+
+// TryMatchString is like MatchString but returns matching failures, such as
+// pcre.ErrMatchLimit, as an error.
+func (re *Regexp) TryMatchString(s string) (bool, error) { return re.exec(s, 0, 0, nil) }
+
+// MatchPartial reports whether b matches re, could still match if more input
+// followed it, or neither. This is meant for validating input while it is
+// being typed, with a pattern anchored at both ends.
+func (re *Regexp) MatchPartial(b []byte) pcre.MatchResult {
+	return re.MatchPartialString(bytesString(b))
+}
+
+// MatchPartialString is like MatchPartial but matches a string.
+func (re *Regexp) MatchPartialString(s string) pcre.MatchResult {
+	var ctx pcre.MatchContext
+	extra, err := re.begin(&ctx, pcre.PartialSoft)
+	if err != nil {
+		panic(err)
 	}
+	defer re.end(&ctx)
 
-	return true
+	var oVector [3]int
+	partial, err := re.pcre.ExecPartial(extra, &ctx, s, 0, pcre.PartialSoft, oVector[:])
+	if err != nil {
+		panic(err)
+	}
+	return partial.Result
 }
 
 func MatchReader(_ string, _ io.RuneReader) (matched bool, err error) {
@@ -117,7 +377,58 @@ func (re *Regexp) Find(b []byte) []byte {
 	return b[is[0]:is[1]]
 }
 
-func (re *Regexp) FindString(s string) string { return string(re.Find([]byte(s))) }
+func (re *Regexp) FindString(s string) string {
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		return ""
+	}
+	return s[loc[0]:loc[1]]
+}
+
+// FindMark is like Find, but also returns the name of the last (*MARK:name)
+// passed on the path that matched, or "" if there was none. In a pattern
+// combining rules like `(*MARK:a)rule1|(*MARK:b)rule2` it tells which one
+// matched.
+func (re *Regexp) FindMark(b []byte) ([]byte, string) {
+	loc, mark := re.FindIndexMark(b)
+	if loc == nil {
+		return nil, ""
+	}
+	return b[loc[0]:loc[1]], mark
+}
+
+// FindStringMark is like FindMark but matches a string.
+func (re *Regexp) FindStringMark(s string) (string, string) {
+	loc, mark := re.findIndexMark(s)
+	if loc == nil {
+		return "", ""
+	}
+	return s[loc[0]:loc[1]], mark
+}
+
+// FindIndexMark is like FindIndex, but also returns the name of the last
+// (*MARK:name) passed on the path that matched.
+func (re *Regexp) FindIndexMark(b []byte) (loc []int, mark string) {
+	return re.findIndexMark(bytesString(b))
+}
+
+func (re *Regexp) findIndexMark(s string) (loc []int, mark string) {
+	var ctx pcre.MatchContext
+	extra, err := re.begin(&ctx, 0)
+	if err != nil {
+		panic(err)
+	}
+	defer re.end(&ctx)
+	ctx.Mark = &mark
+
+	var oVector [3]int
+	if e := re.pcre.ExecContext(extra, &ctx, s, 0, 0, oVector[:]); e == pcre.ErrNoMatch {
+		return nil, ""
+	} else if e < 0 {
+		panic(e.Detail(oVector[:]))
+	}
+	return []int{oVector[0], oVector[1]}, mark
+}
 
 func (re *Regexp) FindAll(b []byte, n int) [][]byte {
 	var c [][]byte
@@ -127,49 +438,32 @@ func (re *Regexp) FindAll(b []byte, n int) [][]byte {
 	return c
 }
 
-func (re *Regexp) FindAllIndex(b []byte, n int) [][]int {
-	var locs [][]int
-	var options pcre.Option
-	for start := 0; start <= len(b) && n != 0; n-- {
-		oVector := make([]int, 3)
-		if e := re.pcre.Exec(nil, string(b), start, options, oVector); e == pcre.ErrNoMatch {
-			break
-		} else if e < 0 {
-			log.Panicf("while mathcing %q[%d:]: e: %d", b, start, e)
-		} else {
-			locs = append(locs, []int{oVector[0], oVector[1]})
-		}
-		options |= pcre.NotEmptyAtStart
-		start = oVector[1]
-	}
-
-	return locs
-}
+func (re *Regexp) FindAllIndex(b []byte, n int) [][]int { return re.findAll(bytesString(b), n, 1) }
 
 func (re *Regexp) FindAllString(s string, n int) []string {
 	var ss []string
-	for _, s := range re.FindAll([]byte(s), n) {
-		ss = append(ss, string(s))
+	for _, loc := range re.findAll(s, n, 1) {
+		ss = append(ss, s[loc[0]:loc[1]])
 	}
 	return ss
 }
 
-func (re *Regexp) FindAllStringIndex(s string, n int) [][]int { return re.FindAllIndex([]byte(s), n) }
+func (re *Regexp) FindAllStringIndex(s string, n int) [][]int { return re.findAll(s, n, 1) }
+
+func (re *Regexp) FindIndex(b []byte) []int { return re.FindStringIndex(bytesString(b)) }
 
-func (re *Regexp) FindIndex(b []byte) []int {
-	oVector := make([]int, 3)
+func (re *Regexp) FindStringIndex(s string) []int {
+	var oVector [3]int
 
-	if e := re.pcre.Exec(nil, string(b), 0, 0, oVector); e < pcre.ErrNoMatch {
-		log.Panicf("e: %d", e)
-	} else if e >= 0 {
+	if matched, err := re.exec(s, 0, 0, oVector[:]); err != nil {
+		panic(err)
+	} else if matched {
 		return []int{oVector[0], oVector[1]}
 	}
 
 	return nil
 }
 
-func (re *Regexp) FindStringIndex(s string) []int { return re.FindIndex([]byte(s)) }
-
 func (re *Regexp) FindReaderIndex(r io.RuneReader) (loc []int) {
 	data, err := readAllRunes(r)
 	if err != nil {
@@ -181,10 +475,14 @@ func (re *Regexp) FindReaderIndex(r io.RuneReader) (loc []int) {
 
 func (re *Regexp) FindAllStringSubmatch(s string, n int) [][]string {
 	var rs [][]string
-	for _, bbs := range re.FindAllSubmatch([]byte(s), n) {
+	for _, locs := range re.findAll(s, n, 1+re.numSubexp) {
 		var ss []string
-		for _, bs := range bbs {
-			ss = append(ss, string(bs))
+		for i := 0; i < len(locs); i += 2 {
+			if locs[i] == -1 || locs[i+1] == -1 {
+				ss = append(ss, "")
+			} else {
+				ss = append(ss, s[locs[i]:locs[i+1]])
+			}
 		}
 		rs = append(rs, ss)
 	}
@@ -192,7 +490,7 @@ func (re *Regexp) FindAllStringSubmatch(s string, n int) [][]string {
 }
 
 func (re *Regexp) FindAllStringSubmatchIndex(s string, n int) [][]int {
-	return re.FindAllSubmatchIndex([]byte(s), n)
+	return re.findAll(s, n, 1+re.numSubexp)
 }
 
 func (re *Regexp) FindAllSubmatch(b []byte, n int) [][][]byte {
@@ -212,21 +510,53 @@ func (re *Regexp) FindAllSubmatch(b []byte, n int) [][][]byte {
 }
 
 func (re *Regexp) FindAllSubmatchIndex(b []byte, n int) [][]int {
-	var locs [][]int
-	var options pcre.Option
-	for start := 0; start <= len(b) && n != 0; n-- {
-		oVector := make([]int, (1+re.pcre.CaptureCount())*3)
-		if e := re.pcre.Exec(nil, string(b), start, options, oVector); e == pcre.ErrNoMatch {
-			break
-		} else if e < 0 {
-			log.Panicf("while matching %q[%d:]: %d", b, start, e)
-		} else {
-			locs = append(locs, oVector[:(1+re.pcre.CaptureCount())*2])
-		}
-		options |= pcre.NotEmptyAtStart
-		start = oVector[1]
+	return re.findAll(bytesString(b), n, 1+re.numSubexp)
+}
+
+// Count returns the number of successive non-overlapping matches of re in
+// b, the number of locations FindAllIndex(b, -1) would return, without
+// collecting them.
+func (re *Regexp) Count(b []byte) int { return re.CountString(bytesString(b)) }
+
+// CountString is like Count but matches a string.
+func (re *Regexp) CountString(s string) int {
+	var ctx pcre.MatchContext
+	extra, err := re.begin(&ctx, pcre.NotEmptyAtStart)
+	if err != nil {
+		panic(err)
 	}
+	defer re.end(&ctx)
 
+	n, err := re.pcre.Count(extra, &ctx, s, 0, 0)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// findAll returns the first pairs offset pairs of up to n successive
+// matches in s, all n if n < 0, with a single ExecAll.
+func (re *Regexp) findAll(s string, n, pairs int) [][]int {
+	if n == 0 {
+		return nil
+	}
+
+	var ctx pcre.MatchContext
+	extra, err := re.begin(&ctx, pcre.NotEmptyAtStart)
+	if err != nil {
+		panic(err)
+	}
+	defer re.end(&ctx)
+
+	offsets, err := re.pcre.ExecAll(extra, &ctx, s, 0, 0, n, pairs)
+	if err != nil {
+		panic(err)
+	}
+
+	var locs [][]int
+	for i := 0; i < len(offsets); i += 2 * pairs {
+		locs = append(locs, offsets[i:i+2*pairs:i+2*pairs])
+	}
 	return locs
 }
 
@@ -245,27 +575,29 @@ func (re *Regexp) FindSubmatch(b []byte) [][]byte {
 
 func (re *Regexp) FindStringSubmatch(s string) []string {
 	var subs []string
-	for _, sub := range re.FindSubmatch([]byte(s)) {
-		subs = append(subs, string(sub))
+	locs := re.FindStringSubmatchIndex(s)
+	for i := 0; i < len(locs); i += 2 {
+		if locs[i] == -1 || locs[i+1] == -1 {
+			subs = append(subs, "")
+		} else {
+			subs = append(subs, s[locs[i]:locs[i+1]])
+		}
 	}
 	return subs
 }
 
 func (re *Regexp) FindSubmatchIndex(b []byte) []int {
-	var t = string(b) == "aacc" || re.expr == "(a){0}"
-	oVector := make([]int, (1+re.pcre.CaptureCount())*3)
-	if e := re.pcre.Exec(nil, string(b), 0, 0, oVector); e == pcre.ErrNoMatch {
-		return nil
-	} else if e < 0 {
-		log.Panicf("e: %d", e)
-	} else if t {
-		log.Printf("expr: %q, b: %q, e: %d, oVector: %#v, %#v", re.expr, b, e, oVector, oVector[:(1+re.pcre.CaptureCount())*2])
-	}
-	return oVector[:(1+re.pcre.CaptureCount())*2]
+	return re.FindStringSubmatchIndex(bytesString(b))
 }
 
 func (re *Regexp) FindStringSubmatchIndex(s string) []int {
-	return re.FindSubmatchIndex([]byte(s))
+	oVector := make([]int, (1+re.numSubexp)*3)
+	if matched, err := re.exec(s, 0, 0, oVector); err != nil {
+		panic(err)
+	} else if !matched {
+		return nil
+	}
+	return oVector[:(1+re.numSubexp)*2]
 }
 
 func (re *Regexp) FindReaderSubmatchIndex(r io.RuneReader) []int {
@@ -286,10 +618,63 @@ func (re *Regexp) Split(s string, n int) []string { return nil }
 func (re *Regexp) String() string { return re.expr } // TODO
 
 func (re *Regexp) NumSubExp() int {
-	return re.pcre.CaptureCount() // TODO - is this correct? or do they mean *all* groups?
+	return re.numSubexp // TODO - is this correct? or do they mean *all* groups?
+}
+
+func (re *Regexp) SubExpNames() []string {
+	if err := re.acquire(); err != nil {
+		panic(err)
+	}
+	defer re.release()
+	return re.pcre.NameTable()
+}
+
+// SubexpIndex returns the index of the first subexpression called name, or
+// -1 if there is none. Patterns may use a name more than once, see
+// SubexpIndices and MatchedSubexpIndex.
+func (re *Regexp) SubexpIndex(name string) int {
+	if indices := re.SubexpIndices(name); len(indices) > 0 {
+		return indices[0]
+	}
+	return -1
+}
+
+// SubexpIndices returns the indices of all subexpressions called name, in
+// increasing order.
+func (re *Regexp) SubexpIndices(name string) []int {
+	if err := re.acquire(); err != nil {
+		panic(err)
+	}
+	defer re.release()
+
+	indices, err := re.pcre.StringNumbers(name)
+	if err != nil {
+		return nil
+	}
+	return indices
 }
 
-func (re *Regexp) SubExpNames() []string { return re.pcre.NameTable() }
+// MatchedSubexpIndex returns the index of the first subexpression called
+// name that took part in match, as returned by FindSubmatchIndex, or -1 if
+// none did. In `(?<id>\d+)|(?<id>[a-f]+)` it tells which of the two groups
+// called id matched.
+func (re *Regexp) MatchedSubexpIndex(name string, match []int) int {
+	for _, i := range re.SubexpIndices(name) {
+		if 2*i+1 < len(match) && match[2*i] >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// bytesString returns b as a string without copying it, for subjects that
+// libpcre only reads during the call. Offsets into the string are offsets
+// into b.
+func bytesString(b []byte) string { return unsafe.String(unsafe.SliceData(b), len(b)) }
+
+// stringBytes is the reverse of bytesString. The slice shares the memory of
+// s and must only be read.
+func stringBytes(s string) []byte { return unsafe.Slice(unsafe.StringData(s), len(s)) }
 
 func readAllRunes(r io.RuneReader) ([]byte, error) {
 	data := new(bytes.Buffer)
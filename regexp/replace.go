@@ -9,17 +9,17 @@ import (
 func (re *Regexp) Expand(dst []byte, template []byte, src []byte, match []int) []byte { panic("TODO") }
 
 func (re *Regexp) ExpandString(dst []byte, template string, src string, match []int) []byte {
-	return re.Expand(dst, []byte(template), []byte(src), match)
+	return re.Expand(dst, stringBytes(template), stringBytes(src), match)
 }
 
 func (re *Regexp) ReplaceAll(src, repl []byte) []byte {
 	return re.replaceAll(src, func(dst []byte, match []int) []byte {
-		return re.expand(dst, string(repl), src, match)
+		return re.expand(dst, bytesString(repl), src, match)
 	})
 }
 
 func (re *Regexp) ReplaceAllString(original, replacement string) string {
-	return string(re.ReplaceAll([]byte(original), []byte(replacement)))
+	return string(re.ReplaceAll(stringBytes(original), stringBytes(replacement)))
 }
 
 func (re *Regexp) ReplaceAllLiteral(original, replacement []byte) []byte {
@@ -55,11 +55,8 @@ func (re *Regexp) expand(dst []byte, template string, src []byte, match []int) [
 				dst = append(dst, src[match[2*num]:match[2*num+1]]...)
 			}
 		} else {
-			for i, namei := range re.SubExpNames() {
-				if name == namei && 2*i+1 < len(match) && match[2*i] >= 0 {
-					dst = append(dst, src[match[2*i]:match[2*i+1]]...)
-					break
-				}
+			if i := re.MatchedSubexpIndex(name, match); i >= 0 {
+				dst = append(dst, src[match[2*i]:match[2*i+1]]...)
 			}
 		}
 	}
@@ -135,7 +132,7 @@ func (re *Regexp) replaceAll(original []byte, replacement func([]byte, []int) []
 }
 
 func (re *Regexp) ReplaceAllLiteralString(original, replacement string) string {
-	return string(re.ReplaceAllLiteral([]byte(original), []byte(replacement)))
+	return string(re.ReplaceAllLiteral(stringBytes(original), stringBytes(replacement)))
 }
 
 func (re *Regexp) ReplaceAllFunc(original []byte, replacement func([]byte) []byte) []byte {
@@ -145,7 +142,7 @@ func (re *Regexp) ReplaceAllFunc(original []byte, replacement func([]byte) []byt
 }
 
 func (re *Regexp) ReplaceAllStringFunc(original string, replacement func(string) string) string {
-	return string(re.replaceAll([]byte(original), func(dst []byte, match []int) []byte {
-		return append(dst, []byte(replacement(original[match[0]:match[1]]))...)
+	return string(re.replaceAll(stringBytes(original), func(dst []byte, match []int) []byte {
+		return append(dst, replacement(original[match[0]:match[1]])...)
 	}))
 }
@@ -0,0 +1,194 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+// #include <pcre.h>
+// #include <string.h>
+//
+// static pcre *copy_pattern(const void *data, size_t size) {
+//     pcre *code = pcre_malloc(size);
+//     if (code != NULL) {
+//         memcpy(code, data, size);
+//     }
+//     return code;
+// }
+//
+// // The study data goes in the same block as the pcre_extra, the way
+// // pcre_study allocates it, so that pcre_free_study releases both.
+// static pcre_extra *copy_study_data(const void *data, size_t size) {
+//     pcre_extra *extra = pcre_malloc(sizeof(pcre_extra) + size);
+//     if (extra != NULL) {
+//         memset(extra, 0, sizeof(pcre_extra));
+//         extra->flags = PCRE_EXTRA_STUDY_DATA;
+//         extra->study_data = (char *)extra + sizeof(pcre_extra);
+//         memcpy(extra->study_data, data, size);
+//     }
+//     return extra;
+// }
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// savedMagic starts the data written by Marshal.
+const savedMagic = "GoPCRE\x00\x01"
+
+// ErrVersionMismatch is returned by Load for patterns that were saved by a
+// different libpcre version or build.
+var ErrVersionMismatch = errors.New("pcre: pattern saved by a different libpcre")
+
+var errCorrupt = errors.New("pcre: corrupt saved pattern")
+
+var errTables = errors.New("pcre: saved pattern needs the tables it was compiled with")
+
+// MarshalBinary saves the compiled pattern, for restoring it with Load
+// without compiling it again.
+func (pcre *PCRE) MarshalBinary() ([]byte, error) { return pcre.Marshal(nil, 0) }
+
+// Marshal saves the compiled pattern together with its study data. JIT code
+// cannot be saved, so when studyOptions, the options extra was studied with,
+// ask for JIT compiling Load studies the pattern again instead. The saved
+// data can only be loaded by the same libpcre version. Character tables are
+// not saved, a pattern compiled with tables has to be given them by Load.
+func (pcre *PCRE) Marshal(extra *PCREExtra, studyOptions StudyOption) ([]byte, error) {
+	var size C.size_t
+	if err := pcre.fullinfo(nil, InfoSize, unsafe.Pointer(&size)); err != nil {
+		return nil, err
+	}
+
+	var study []byte
+	if extra != nil && extra.flags&C.PCRE_EXTRA_STUDY_DATA != 0 && extra.study_data != nil {
+		var studySize C.size_t
+		if err := pcre.fullinfo(extra, InfoStudySize, unsafe.Pointer(&studySize)); err != nil {
+			return nil, err
+		}
+		study = C.GoBytes(extra.study_data, C.int(studySize))
+	}
+
+	config := Config()
+	var buf bytes.Buffer
+	buf.WriteString(savedMagic)
+	writeSaved(&buf, []byte(config.Version))
+	binary.Write(&buf, binary.LittleEndian, uint32(config.LinkSize))
+	binary.Write(&buf, binary.LittleEndian, uint32(studyOptions))
+	var hasTables uint32
+	if tablesOf(pcre) != nil {
+		hasTables = 1
+	}
+	binary.Write(&buf, binary.LittleEndian, hasTables)
+	writeSaved(&buf, C.GoBytes(unsafe.Pointer(pcre), C.int(size)))
+	writeSaved(&buf, study)
+	return buf.Bytes(), nil
+}
+
+// Load restores a pattern saved by Marshal or MarshalBinary. It returns the
+// study data, or nil when none was saved. Patterns saved on a machine with a
+// different byte order are converted. tables must be the tables the pattern
+// was compiled with, or nil if it was compiled without.
+func Load(data []byte, tables *Tables) (*PCRE, *PCREExtra, error) {
+	r := bytes.NewReader(data)
+	magic := make([]byte, len(savedMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != savedMagic {
+		return nil, nil, errCorrupt
+	}
+
+	var linkSize, studyOptions, hasTables uint32
+	version, err := readSaved(r)
+	if err == nil {
+		err = binary.Read(r, binary.LittleEndian, &linkSize)
+	}
+	if err == nil {
+		err = binary.Read(r, binary.LittleEndian, &studyOptions)
+	}
+	if err == nil {
+		err = binary.Read(r, binary.LittleEndian, &hasTables)
+	}
+	if err != nil {
+		return nil, nil, errCorrupt
+	}
+	if config := Config(); string(version) != config.Version || int(linkSize) != config.LinkSize {
+		return nil, nil, fmt.Errorf("%w: saved by %s, running %s", ErrVersionMismatch, version, config.Version)
+	}
+	if (hasTables != 0) != (tables != nil) {
+		return nil, nil, errTables
+	}
+
+	pattern, err := readSaved(r)
+	if err != nil || len(pattern) == 0 {
+		return nil, nil, errCorrupt
+	}
+	study, err := readSaved(r)
+	if err != nil {
+		return nil, nil, errCorrupt
+	}
+
+	code := C.copy_pattern(unsafe.Pointer(&pattern[0]), C.size_t(len(pattern)))
+	if code == nil {
+		return nil, nil, ErrNoMemory
+	}
+	re := (*PCRE)(unsafe.Pointer(code))
+	track(unsafe.Pointer(code))
+
+	// Studying again for JIT compiling also recreates the study data.
+	jit := StudyOption(studyOptions)&(StudyJITCompile|StudyJITPartialSoftCompile|StudyJITPartialHardCompile) != 0
+
+	var extra *C.pcre_extra
+	if len(study) > 0 && !jit {
+		if extra = C.copy_study_data(unsafe.Pointer(&study[0]), C.size_t(len(study))); extra == nil {
+			re.Free()
+			return nil, nil, ErrNoMemory
+		}
+	}
+
+	// This also sets the tables of the pattern, which are not saved.
+	var tablesPtr *C.uchar
+	if tables != nil {
+		tablesPtr = tables.ptr
+		holdTables(re, tables)
+	}
+	if rc := C.pcre_pattern_to_host_byte_order(code, extra, tablesPtr); rc != 0 {
+		if extra != nil {
+			C.pcre_free_study(extra)
+		}
+		re.Free()
+		return nil, nil, Error(rc)
+	}
+
+	if jit {
+		pcreExtra, err := Study(re, StudyOption(studyOptions), nil)
+		if err != nil {
+			re.Free()
+			return nil, nil, err
+		}
+		return re, pcreExtra, nil
+	}
+	if extra != nil {
+		track(unsafe.Pointer(extra))
+	}
+	return re, (*PCREExtra)(unsafe.Pointer(extra)), nil
+}
+
+func writeSaved(buf *bytes.Buffer, data []byte) {
+	binary.Write(buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
+func readSaved(r *bytes.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, err
+	}
+	if int64(size) > int64(r.Len()) {
+		return nil, errCorrupt
+	}
+	data := make([]byte, size)
+	_, err := io.ReadFull(r, data)
+	return data, err
+}
@@ -0,0 +1,99 @@
+//go:build !pcre2
+// +build !pcre2
+
+package pcre
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalLoad(t *testing.T) {
+	re := mustCompile(t, `(\w+)@(\w+)`, Caseless)
+
+	for _, studyOptions := range []StudyOption{0, StudyJITCompile} {
+		extra, err := Study(re, studyOptions, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := re.Marshal(extra, studyOptions)
+		extra.Free()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, loadedExtra, err := Load(data, nil)
+		if err != nil {
+			t.Fatalf("Load of a pattern studied with %v: %v", studyOptions, err)
+		}
+		oVector := make([]int, 9)
+		if e := loaded.Exec(loadedExtra, "to: Foo@bar.example", 0, 0, oVector); e < 0 {
+			t.Errorf("Exec with %v = %v", studyOptions, e)
+		} else if want := []int{4, 11, 4, 7, 8, 11}; !reflect.DeepEqual(oVector[:6], want) {
+			t.Errorf("Exec with %v matched %v, want %v", studyOptions, oVector[:6], want)
+		}
+
+		info, err := loaded.Info(loadedExtra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.MinLength != 3 {
+			t.Errorf("loaded study data with %v: MinLength %d, want 3", studyOptions, info.MinLength)
+		}
+		if studyOptions != 0 && info.JIT != Config().JIT {
+			t.Errorf("loaded pattern JIT compiled %v, libpcre has JIT %v", info.JIT, Config().JIT)
+		}
+		loadedExtra.Free()
+		loaded.Free()
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	data, err := mustCompile(t, `a+`, 0).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The version follows the magic and its length.
+	version := append([]byte(nil), data...)
+	version[len(savedMagic)+4] ^= 0xff
+	if _, _, err := Load(version, nil); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("Load of another version: %v, want ErrVersionMismatch", err)
+	}
+
+	for _, corrupt := range [][]byte{nil, data[:len(savedMagic)], data[:len(data)-1]} {
+		if _, _, err := Load(corrupt, nil); err != errCorrupt {
+			t.Errorf("Load of %d of %d bytes: %v, want errCorrupt", len(corrupt), len(data), err)
+		}
+	}
+
+	if _, _, err := Load(data, NewTablesFromClasses(Latin1)); err != errTables {
+		t.Errorf("Load with tables of a pattern compiled without: %v, want errTables", err)
+	}
+}
+
+func TestLoadTables(t *testing.T) {
+	tables := NewTablesFromClasses(Latin1)
+	re, err := Compile(`\xe9`, Caseless, tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := re.MarshalBinary()
+	re.Free()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Load(data, nil); err != errTables {
+		t.Errorf("Load without tables: %v, want errTables", err)
+	}
+	loaded, _, err := Load(data, tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Free()
+	if e := loaded.Exec(nil, "\xc9", 0, 0, nil); e < 0 {
+		t.Errorf("loaded Latin-1 caseless pattern does not match \\xc9: %v", e)
+	}
+}
@@ -0,0 +1,112 @@
+package pcre
+
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// emptySubject stands in for empty subjects, libpcre rejects NULL even
+// with a length of 0.
+var emptySubject [1]byte
+
+// subjectPtr returns a pointer to the bytes of subject for passing to C
+// without copying them. cgo keeps the memory in place for the duration of
+// the call, and libpcre only reads it.
+func subjectPtr(subject string) *C.char {
+	if len(subject) == 0 {
+		return (*C.char)(unsafe.Pointer(&emptySubject[0]))
+	}
+	return (*C.char)(unsafe.Pointer(unsafe.StringData(subject)))
+}
+
+// bytesToString returns b as a string without copying it. The string must
+// only be used while b is not modified.
+func bytesToString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+// oVectorPool holds the C int vectors Exec passes offsets through, the Go
+// int is wider than the C one on 64 bit platforms.
+var oVectorPool = sync.Pool{
+	New: func() interface{} { return new([]C.int) },
+}
+
+// getOVector returns a vector of size ints from the pool, hand it back with
+// putOVector.
+func getOVector(size int) *[]C.int {
+	v := oVectorPool.Get().(*[]C.int)
+	if cap(*v) < size {
+		*v = make([]C.int, size)
+	}
+	*v = (*v)[:size]
+	return v
+}
+
+func putOVector(v *[]C.int) { oVectorPool.Put(v) }
+
+// copyOVector converts the offsets libpcre stored in from into to.
+func copyOVector(to []int, from []C.int) {
+	for n, i := range from {
+		to[n] = int(i)
+	}
+}
+
+// execAllChunk is the number of matches ExecAll collects per call into C.
+const execAllChunk = 64
+
+// subjectsPool holds the buffers ExecBatch packs its subjects into.
+var subjectsPool = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
+
+// batch lays out the subjects of an ExecBatch call for C: their bytes end to
+// end, and the lengths, results and match offsets in one int vector.
+type batch struct {
+	count    int
+	subjects *[]byte
+	ints     *[]C.int
+}
+
+func newBatch(subjects []string) batch {
+	total := 0
+	for _, s := range subjects {
+		total += len(s)
+	}
+
+	// One byte more keeps the pointer to the buffer valid for empty subjects.
+	buf := subjectsPool.Get().(*[]byte)
+	if cap(*buf) < total+1 {
+		*buf = make([]byte, 0, total+1)
+	}
+	*buf = (*buf)[:0]
+
+	ints := getOVector(4 * len(subjects))
+	for i, s := range subjects {
+		*buf = append(*buf, s...)
+		(*ints)[i] = C.int(len(s))
+	}
+	return batch{count: len(subjects), subjects: buf, ints: ints}
+}
+
+func (b batch) subjectsPtr() *C.char {
+	return (*C.char)(unsafe.Pointer(unsafe.SliceData(*b.subjects)))
+}
+
+func (b batch) lengths() *C.int { return &(*b.ints)[0] }
+func (b batch) results() *C.int { return &(*b.ints)[b.count] }
+func (b batch) locs() *C.int    { return &(*b.ints)[2*b.count] }
+
+// done copies the results and offsets C stored to results and, if non-nil,
+// locs, and returns the buffers to their pools.
+func (b batch) done(results []Error, locs []int) {
+	for i, r := range (*b.ints)[b.count : 2*b.count] {
+		results[i] = Error(r)
+	}
+	if locs != nil {
+		copyOVector(locs[:2*b.count], (*b.ints)[2*b.count:])
+	}
+	subjectsPool.Put(b.subjects)
+	putOVector(b.ints)
+}
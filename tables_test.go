@@ -0,0 +1,76 @@
+package pcre
+
+import (
+	"runtime"
+	"testing"
+)
+
+// testLatin1Tables checks that tables make caseless matching and \w follow
+// Latin-1 for é and É, which the default tables leave alone.
+func testLatin1Tables(t *testing.T, tables *Tables) {
+	t.Helper()
+	for _, test := range []struct {
+		expr    string
+		options CompileOption
+		subject string
+	}{
+		{`\xe9`, Caseless, "\xc9"},
+		{`\xc9`, Caseless, "\xe9"},
+		{`^\w$`, 0, "\xe9"},
+		{`^[[:upper:]]$`, 0, "\xc9"},
+	} {
+		for _, tables := range []*Tables{nil, tables} {
+			re, err := Compile(test.expr, test.options, tables)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := re.Exec(nil, test.subject, 0, 0, nil)
+			re.Free()
+			if want := tables != nil; (e >= 0) != want {
+				t.Errorf("%s with %v and tables %v: matches %q = %v, want %v", test.expr, test.options, tables != nil, test.subject, e >= 0, want)
+			}
+		}
+	}
+}
+
+func TestTablesFromClasses(t *testing.T) {
+	testLatin1Tables(t, NewTablesFromClasses(Latin1))
+}
+
+func TestTablesLocale(t *testing.T) {
+	if _, err := NewTables("no_SUCH.locale"); err == nil {
+		t.Error("NewTables of an unknown locale did not fail")
+	}
+
+	for _, locale := range []string{"de_DE.ISO-8859-1", "en_US.ISO-8859-1", "fr_FR.ISO-8859-1"} {
+		if tables, err := NewTables(locale); err == nil {
+			testLatin1Tables(t, tables)
+			return
+		}
+	}
+	t.Skip("no ISO-8859-1 locale installed")
+}
+
+func TestTablesLifetime(t *testing.T) {
+	re, err := Compile(`^\w+$`, Caseless, NewTablesFromClasses(Latin1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tables are only referenced by the pattern now, collecting them
+	// would leave it reading freed memory.
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+	if tablesOf(re) == nil {
+		t.Fatal("tables released while the pattern is alive")
+	}
+	if e := re.Exec(nil, "caf\xe9", 0, 0, nil); e < 0 {
+		t.Errorf("Exec after GC = %v", e)
+	}
+
+	re.Free()
+	if tablesOf(re) != nil {
+		t.Error("tables still held after the pattern was freed")
+	}
+}